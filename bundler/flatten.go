@@ -0,0 +1,61 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package bundler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/index"
+)
+
+// FlattenDocument will take a v3.Document and return a new, self-contained v3.Document with every internal and
+// external reference replaced inline. The supplied document is not modified.
+//
+// Circular references cannot be inlined, so if any are found, an *index.CircularReferenceError is returned for
+// each one. Circular references that were ignored using IgnoreArrayCircularReferences or
+// IgnorePolymorphicCircularReferences are safe, and are left as references in the flattened document.
+func FlattenDocument(model *v3.Document) (*v3.Document, error) {
+	if model == nil || model.Rolodex == nil || model.Rolodex.GetRootIndex() == nil {
+		return nil, ErrInvalidModel
+	}
+
+	rolodex := model.Rolodex
+	indexes := append([]*index.SpecIndex{rolodex.GetRootIndex()}, rolodex.GetIndexes()...)
+	var errs []error
+	for _, idx := range indexes {
+		for _, c := range idx.GetCircularReferences() {
+			errs = append(errs, index.NewCircularReferenceError(
+				fmt.Errorf("cannot flatten circular reference: %s", c.GenerateJourneyPath()), c))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	flat, err := model.RenderInline()
+	if err != nil {
+		return nil, err
+	}
+
+	// the flattened document has no references to follow, other than ignored circular references.
+	idxConfig := rolodex.GetRootIndex().GetConfig()
+	config := datamodel.NewDocumentConfiguration()
+	if idxConfig != nil {
+		config.IgnoreArrayCircularReferences = idxConfig.IgnoreArrayCircularReferences
+		config.IgnorePolymorphicCircularReferences = idxConfig.IgnorePolymorphicCircularReferences
+	}
+	doc, err := libopenapi.NewDocumentWithConfiguration(flat, config)
+	if err != nil {
+		return nil, err
+	}
+	v3Doc, buildErrs := doc.BuildV3Model()
+	if v3Doc == nil {
+		return nil, errors.Join(append([]error{ErrInvalidModel}, buildErrs...)...)
+	}
+	return &v3Doc.Model, errors.Join(buildErrs...)
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package bundler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildFlattenModel(t *testing.T, spec string, config *datamodel.DocumentConfiguration) *v3.Document {
+	doc, err := libopenapi.NewDocumentWithConfiguration([]byte(spec), config)
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	require.NotNil(t, m)
+	return &m.Model
+}
+
+func TestFlattenDocument(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: flatten
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	model := buildFlattenModel(t, spec, datamodel.NewDocumentConfiguration())
+	flat, err := FlattenDocument(model)
+	require.NoError(t, err)
+
+	rendered, _ := flat.Render()
+	assert.NotContains(t, string(rendered), "$ref")
+
+	get := flat.Paths.PathItems.GetOrZero("/pets").Get
+	assert.Equal(t, "limit", get.Parameters[0].Name)
+	pet := get.Responses.Codes.GetOrZero("200").Content.GetOrZero("application/json").Schema.Schema()
+	owner := pet.Properties.GetOrZero("owner").Schema()
+	assert.Equal(t, []string{"string"}, owner.Properties.GetOrZero("name").Schema().Type)
+
+	// the original model still has its references.
+	rendered, _ = model.Render()
+	assert.Contains(t, string(rendered), "$ref: '#/components/schemas/Pet'")
+}
+
+func TestFlattenDocument_ExternalReferences(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "pet.yaml"), []byte(`type: object
+properties:
+  name:
+    type: string`), 0o644)
+
+	spec := `openapi: 3.1.0
+info:
+  title: flatten
+  version: 1.0.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml'`
+
+	model := buildFlattenModel(t, spec, &datamodel.DocumentConfiguration{
+		BasePath:            dir,
+		AllowFileReferences: true,
+	})
+	flat, err := FlattenDocument(model)
+	require.NoError(t, err)
+
+	rendered, _ := flat.Render()
+	assert.NotContains(t, string(rendered), "$ref")
+	pet := flat.Components.Schemas.GetOrZero("Pet").Schema()
+	assert.Equal(t, []string{"string"}, pet.Properties.GetOrZero("name").Schema().Type)
+}
+
+func TestFlattenDocument_Circular(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: flatten
+  version: 1.0.0
+components:
+  schemas:
+    Obj:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Obj'
+      required:
+        - children`
+
+	model := buildFlattenModel(t, spec, datamodel.NewDocumentConfiguration())
+	flat, err := FlattenDocument(model)
+	assert.Nil(t, flat)
+
+	var circErr *index.CircularReferenceError
+	require.True(t, errors.As(err, &circErr))
+	assert.Equal(t, []string{"#/components/schemas/Obj", "#/components/schemas/Obj"}, circErr.Journey)
+
+	// ignored circular references are safe, and are kept as references.
+	model = buildFlattenModel(t, spec, &datamodel.DocumentConfiguration{IgnoreArrayCircularReferences: true})
+	flat, err = FlattenDocument(model)
+	require.NoError(t, err)
+	rendered, _ := flat.Render()
+	assert.Contains(t, string(rendered), "$ref: '#/components/schemas/Obj'")
+}
+
+func TestFlattenDocument_AlreadyFlat(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: flatten
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Pet:
+      type: object
+`
+
+	model := buildFlattenModel(t, spec, datamodel.NewDocumentConfiguration())
+	flat, err := FlattenDocument(model)
+	require.NoError(t, err)
+
+	original, _ := model.Render()
+	rendered, _ := flat.Render()
+	assert.Equal(t, string(original), string(rendered))
+}
+
+func TestFlattenDocument_Invalid(t *testing.T) {
+	flat, err := FlattenDocument(nil)
+	assert.Nil(t, flat)
+	assert.ErrorIs(t, err, ErrInvalidModel)
+
+	flat, err = FlattenDocument(&v3.Document{})
+	assert.Nil(t, flat)
+	assert.ErrorIs(t, err, ErrInvalidModel)
+}
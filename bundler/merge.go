@@ -0,0 +1,176 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package bundler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrMergeConflict is wrapped by every error returned by MergeDocuments for a definition that exists in more than
+// one document, with different content.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// ErrExtensionOverwritten is wrapped by the warnings returned by MergeDocuments when a root extension exists in more
+// than one document, with different content.
+var ErrExtensionOverwritten = errors.New("extension overwritten")
+
+// MergeDocuments will combine multiple v3.Document models into a single new document. The paths, webhooks, tags,
+// servers and components of every document are combined. The version, info, security, external docs and JSON schema
+// dialect are taken from the first document.
+//
+// Definitions that exist in more than one document are only allowed if they are identical. If they are not, the
+// definition from the earliest document is kept, and an error wrapping ErrMergeConflict is returned for it. Root
+// extensions are merged with the last definition winning, a collision is reported with an error wrapping
+// ErrExtensionOverwritten, these errors are warnings, the merge is not affected.
+//
+// The merged document has no low-level model or index, and shares its objects with the supplied documents, so
+// should be rendered rather than re-used as a source of references.
+func MergeDocuments(docs ...*v3.Document) (*v3.Document, []error) {
+	var errs []error
+	var merged *v3.Document
+	for i, doc := range docs {
+		if doc == nil {
+			errs = append(errs, fmt.Errorf("document %d: %w", i, ErrInvalidModel))
+			continue
+		}
+		if merged == nil {
+			merged = &v3.Document{
+				Version:           doc.Version,
+				Info:              doc.Info,
+				Security:          doc.Security,
+				ExternalDocs:      doc.ExternalDocs,
+				JsonSchemaDialect: doc.JsonSchemaDialect,
+			}
+		}
+		m := &merger{doc: i}
+		m.mergeServers(merged, doc.Servers)
+		m.mergeTags(merged, doc)
+		if doc.Paths != nil {
+			if merged.Paths == nil {
+				merged.Paths = &v3.Paths{}
+			}
+			mergeMap(m, &merged.Paths.PathItems, doc.Paths.PathItems, "path")
+			mergeMap(m, &merged.Paths.Extensions, doc.Paths.Extensions, "paths extension")
+		}
+		mergeMap(m, &merged.Webhooks, doc.Webhooks, "webhook")
+		m.mergeComponents(merged, doc.Components)
+		m.mergeExtensions(merged, doc)
+		errs = append(errs, m.errs...)
+	}
+	if merged == nil {
+		return nil, append(errs, ErrInvalidModel)
+	}
+	return merged, errs
+}
+
+// merger collects the errors found while merging a single document.
+type merger struct {
+	doc  int
+	errs []error
+}
+
+func (m *merger) conflict(kind, name string) {
+	m.errs = append(m.errs, fmt.Errorf("%w: document %d: %s '%s' is already defined differently",
+		ErrMergeConflict, m.doc, kind, name))
+}
+
+func (m *merger) mergeServers(merged *v3.Document, servers []*v3.Server) {
+	for _, s := range servers {
+		var found *v3.Server
+		for _, existing := range merged.Servers {
+			if existing.URL == s.URL {
+				found = existing
+				break
+			}
+		}
+		if found == nil {
+			merged.Servers = append(merged.Servers, s)
+			continue
+		}
+		if !sameRender(found, s) {
+			m.conflict("server", s.URL)
+		}
+	}
+}
+
+func (m *merger) mergeTags(merged *v3.Document, doc *v3.Document) {
+	for _, t := range doc.Tags {
+		var found bool
+		for _, existing := range merged.Tags {
+			if existing.Name == t.Name {
+				found = true
+				if !sameRender(existing, t) {
+					m.conflict("tag", t.Name)
+				}
+				break
+			}
+		}
+		if !found {
+			merged.Tags = append(merged.Tags, t)
+		}
+	}
+}
+
+func (m *merger) mergeComponents(merged *v3.Document, c *v3.Components) {
+	if c == nil {
+		return
+	}
+	if merged.Components == nil {
+		merged.Components = &v3.Components{}
+	}
+	mc := merged.Components
+	mergeMap(m, &mc.Schemas, c.Schemas, "schema")
+	mergeMap(m, &mc.Responses, c.Responses, "response")
+	mergeMap(m, &mc.Parameters, c.Parameters, "parameter")
+	mergeMap(m, &mc.Examples, c.Examples, "example")
+	mergeMap(m, &mc.RequestBodies, c.RequestBodies, "request body")
+	mergeMap(m, &mc.Headers, c.Headers, "header")
+	mergeMap(m, &mc.SecuritySchemes, c.SecuritySchemes, "security scheme")
+	mergeMap(m, &mc.Links, c.Links, "link")
+	mergeMap(m, &mc.Callbacks, c.Callbacks, "callback")
+	mergeMap(m, &mc.PathItems, c.PathItems, "path item")
+	mergeMap(m, &mc.Extensions, c.Extensions, "components extension")
+}
+
+func (m *merger) mergeExtensions(merged *v3.Document, doc *v3.Document) {
+	for k, v := range doc.Extensions.FromOldest() {
+		if merged.Extensions == nil {
+			merged.Extensions = orderedmap.New[string, *yaml.Node]()
+		}
+		if existing, ok := merged.Extensions.Get(k); ok && !sameRender(existing, v) {
+			m.errs = append(m.errs, fmt.Errorf("%w: document %d: extension '%s' replaces an earlier definition",
+				ErrExtensionOverwritten, m.doc, k))
+		}
+		merged.Extensions.Set(k, v)
+	}
+}
+
+// mergeMap adds every entry in src to dst, reporting a conflict for keys that already exist with different content.
+func mergeMap[V any](m *merger, dst **orderedmap.Map[string, V], src *orderedmap.Map[string, V], kind string) {
+	for k, v := range src.FromOldest() {
+		if *dst == nil {
+			*dst = orderedmap.New[string, V]()
+		}
+		if existing, ok := (*dst).Get(k); ok {
+			if !sameRender(existing, v) {
+				m.conflict(kind, k)
+			}
+			continue
+		}
+		(*dst).Set(k, v)
+	}
+}
+
+// sameRender returns true if both values render to the same YAML.
+func sameRender(a, b any) bool {
+	ra, errA := yaml.Marshal(a)
+	rb, errB := yaml.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ra, rb)
+}
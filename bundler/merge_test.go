@@ -0,0 +1,142 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package bundler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDocuments(t *testing.T) {
+	pets := buildFlattenModel(t, `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+servers:
+  - url: https://api.pb33f.io
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+    Error:
+      type: string
+x-team: pets`, datamodel.NewDocumentConfiguration())
+
+	owners := buildFlattenModel(t, `openapi: 3.1.0
+info:
+  title: owners
+  version: 2.0.0
+servers:
+  - url: https://api.pb33f.io
+  - url: https://owners.pb33f.io
+tags:
+  - name: owners
+paths:
+  /owners:
+    get:
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Error:
+      type: string
+webhooks:
+  newOwner:
+    post:
+      description: new owner`, datamodel.NewDocumentConfiguration())
+
+	merged, errs := MergeDocuments(pets, owners)
+	assert.Empty(t, errs)
+	require.NotNil(t, merged)
+
+	assert.Equal(t, "pets", merged.Info.Title)
+	assert.Len(t, merged.Servers, 2)
+	assert.Len(t, merged.Tags, 2)
+	assert.Equal(t, 2, merged.Paths.PathItems.Len())
+	assert.Equal(t, 2, merged.Components.Schemas.Len())
+	assert.Equal(t, 1, merged.Webhooks.Len())
+
+	rendered, err := merged.Render()
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), "/owners:")
+	assert.Contains(t, string(rendered), "$ref: '#/components/schemas/Pet'")
+	assert.Contains(t, string(rendered), "newOwner:")
+	assert.Contains(t, string(rendered), "x-team: pets")
+}
+
+func TestMergeDocuments_Conflicts(t *testing.T) {
+	first := buildFlattenModel(t, `openapi: 3.1.0
+info:
+  title: first
+paths:
+  /pets:
+    get:
+      description: first
+components:
+  schemas:
+    Pet:
+      type: object
+x-team: pets`, datamodel.NewDocumentConfiguration())
+
+	second := buildFlattenModel(t, `openapi: 3.1.0
+info:
+  title: second
+paths:
+  /pets:
+    get:
+      description: second
+components:
+  schemas:
+    Pet:
+      type: string
+x-team: owners`, datamodel.NewDocumentConfiguration())
+
+	merged, errs := MergeDocuments(first, second)
+	require.NotNil(t, merged)
+	require.Len(t, errs, 3)
+
+	assert.True(t, errors.Is(errs[0], ErrMergeConflict))
+	assert.Equal(t, "merge conflict: document 1: path '/pets' is already defined differently", errs[0].Error())
+	assert.True(t, errors.Is(errs[1], ErrMergeConflict))
+	assert.Equal(t, "merge conflict: document 1: schema 'Pet' is already defined differently", errs[1].Error())
+	assert.True(t, errors.Is(errs[2], ErrExtensionOverwritten))
+
+	// conflicts keep the first definition, extensions keep the last.
+	assert.Equal(t, "first", merged.Paths.PathItems.GetOrZero("/pets").Get.Description)
+	assert.Equal(t, []string{"object"}, merged.Components.Schemas.GetOrZero("Pet").Schema().Type)
+	assert.Equal(t, "owners", merged.Extensions.GetOrZero("x-team").Value)
+
+	rendered, err := merged.Render()
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(rendered), "/pets:"))
+}
+
+func TestMergeDocuments_Invalid(t *testing.T) {
+	merged, errs := MergeDocuments()
+	assert.Nil(t, merged)
+	assert.Equal(t, []error{ErrInvalidModel}, errs)
+
+	merged, errs = MergeDocuments(nil)
+	assert.Nil(t, merged)
+	assert.Len(t, errs, 2)
+	assert.True(t, errors.Is(errs[0], ErrInvalidModel))
+}
@@ -4,11 +4,14 @@
 package datamodel
 
 import (
+	"context"
 	"github.com/pb33f/libopenapi/utils"
 	"io/fs"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
+	"time"
 )
 
 // DocumentConfiguration is used to configure the document creation process. It was added in v0.6.0 to allow
@@ -16,6 +19,15 @@ import (
 //
 // The default configuration will set AllowFileReferences to false and AllowRemoteReferences to false, which means
 // any non-local (local being the specification, not the file system) references, will be ignored.
+//
+// Local and remote file systems can be used together (for example, a local root that references both sibling
+// files and https:// URLs). References are routed to a file system by these rules, regardless of the order the
+// file systems were added:
+//   - a reference that is an absolute http:// or https:// URL is opened by the remote file system.
+//   - any other reference is a file path, a relative path is resolved against the file that contains it. Relative
+//     references in local files are opened by the local file system, and relative references in remote files
+//     are resolved against the URL of that file, and opened by the remote file system.
+//   - if more than one local file system could open a path, the one with the most specific base directory is used.
 type DocumentConfiguration struct {
 	// The BaseURL will be the root from which relative references will be resolved from if they can't be found locally.
 	// Schema must be set to "http/https".
@@ -30,6 +42,33 @@ type DocumentConfiguration struct {
 	// Resolves [#132]: https://github.com/pb33f/libopenapi/issues/132
 	RemoteURLHandler utils.RemoteURLHandler
 
+	// HTTPClient is the http.Client that will be used to retrieve remote documents. Use this to configure
+	// proxies, TLS roots, timeouts or auth headers (via a custom transport). If not set, a default client is used.
+	//
+	// If the RemoteURLHandler is also set, the RemoteURLHandler is used instead of the HTTPClient. The HTTPClient is
+	// not used if a RemoteFS is supplied.
+	HTTPClient *http.Client
+
+	// RemoteCacheDir is a local directory used to cache fetched remote documents, keyed by URL. If the server
+	// returns an ETag or Last-Modified header, cached documents are revalidated with a conditional request on the
+	// next run, otherwise they are fetched again. The cached copy is used if the server cannot be reached. Delete
+	// the directory to clear it.
+	//
+	// The cache is not used if a RemoteURLHandler or RemoteFS is supplied.
+	RemoteCacheDir string
+
+	// RemoteRetries is the number of times a remote document will be fetched again, if fetching it fails with a
+	// network error or a 5xx status. 4xx responses are never retried. The default of 0 means no retries.
+	RemoteRetries int
+
+	// RemoteRetryBackoff is the wait before the first retry of a remote fetch, doubling for each retry after that.
+	// A Retry-After header on the failed response is used instead, when present. Defaults to one second.
+	RemoteRetryBackoff time.Duration
+
+	// RemoteRetryMaxWait is the longest wait before a retry of a remote fetch, including waits requested by a
+	// Retry-After header. Defaults to 30 seconds.
+	RemoteRetryMaxWait time.Duration
+
 	// If resolving locally, the BasePath will be the root from which relative references will be resolved from.
 	// It's usually the location of the root specification.
 	//
@@ -42,19 +81,33 @@ type DocumentConfiguration struct {
 	// SpecFilePath is the name of the root specification file (usually named "openapi.yaml").
 	SpecFilePath string
 
+	// RootFilePath is the path of the file the root specification was read from. If BasePath is not set, the
+	// directory of this file is used as the base path, so relative references (like './common.yaml') resolve next
+	// to the root file. An explicit BasePath always wins. If SpecFilePath is not set, the name of this file is used.
+	RootFilePath string
+
 	// FileFilter is a list of specific files to be included by the rolodex when looking up references. If this value
 	// is set, then only these specific files will be included. If this value is not set, then all files will be included.
+	// Entries containing glob metacharacters ('*', '?' or '[') are treated as filepath.Match patterns, for example
+	// '*.schema.yaml'. A file is included if it exactly matches a plain entry, or matches any pattern.
 	FileFilter []string
 
 	// RemoteFS is a filesystem that will be used to retrieve remote documents. If not set, then the rolodex will
-	// use its own internal remote filesystem implementation. The RemoteURLHandler will be used to retrieve remote
+	// use its own internal remote filesystem implementation, which uses the RemoteURLHandler to retrieve remote
 	// documents if it has been set. The default is to use the internal remote filesystem loader.
+	//
+	// A supplied RemoteFS is used as is, the RemoteURLHandler, HTTPClient and RemoteCacheDir are not applied to it.
 	RemoteFS fs.FS
 
 	// LocalFS is a filesystem that will be used to retrieve local documents. If not set, then the rolodex will
 	// use its own internal local filesystem implementation. The default is to use the internal local filesystem loader.
 	LocalFS fs.FS
 
+	// VirtualFS is a map of in-memory files, keyed by path (relative to the BasePath) or by URL, that will be used to
+	// resolve references instead of the local and remote file systems. Missing files are reported the same way as
+	// missing files on disk. This is designed for writing hermetic tests of multi-file specifications.
+	VirtualFS map[string][]byte
+
 	// AllowFileReferences will allow the index to locate relative file references. This is disabled by default.
 	//
 	// This behavior is now driven by the inclusion of a BasePath. If a BasePath is set, then the
@@ -92,10 +145,16 @@ type DocumentConfiguration struct {
 	// IgnoreArrayCircularReferences will skip over checking for circular references in arrays. Sometimes a circular
 	// reference is required to describe a data-shape correctly. Often those shapes are valid circles if the
 	// type of the schema implementing the loop is an array. An empty array would technically break the loop.
-	// So if libopenapi is returning circular references for this use case, then this option should be enabled.
+	// Loops through an array (that does not set minItems) are always reported as legal recursion rather than errors,
+	// this option removes them from the reported circular references altogether.
 	// this is disabled by default, which means array circular references will be checked.
 	IgnoreArrayCircularReferences bool
 
+	// MaxResolveDepth limits how many references deep a chain of references will be followed from a single root
+	// reference. When the limit is exceeded, an error naming the reference is returned. The default of 0 means
+	// there is no limit.
+	MaxResolveDepth int
+
 	// SkipCircularReferenceCheck will skip over checking for circular references. This is disabled by default, which
 	// means circular references will be checked. This is useful for developers building out models that should be
 	// indexed later on.
@@ -114,8 +173,55 @@ type DocumentConfiguration struct {
 	// BundleInlineRefs is used by the bundler module. If set to true, all references will be inlined, including
 	// local references (to the root document) as well as all external references. This is false by default.
 	BundleInlineRefs bool
+
+	// ProgressFunc is called as each stage of building an OpenAPI 3+ document completes, with the name of the stage,
+	// the number of stages completed so far, and the total number of stages. The stages are 'index' and
+	// 'circular references' (rolodex phases), followed by the extraction stages 'info', 'servers', 'tags',
+	// 'components', 'security', 'externalDocs', 'paths' and 'webhooks', then an 'extra' stage for each of the
+	// ExtraExtractors. Calls are never made concurrently.
+	ProgressFunc ProgressFunc
+
+	// DetectDuplicateKeys will check the raw specification for mapping keys that are defined more than once (YAML
+	// parsers silently keep the last one). Each duplicate is reported as a *utils.DuplicateKey, with the JSON pointer
+	// path and line numbers of every definition. Duplicates are added to the document warnings, unless
+	// DuplicateKeysAreErrors is set. Only used when building OpenAPI 3+ documents, this is disabled by default.
+	DetectDuplicateKeys bool
+
+	// DuplicateKeysAreErrors reports duplicate keys as errors instead of warnings. Setting this also enables
+	// DetectDuplicateKeys.
+	DuplicateKeysAreErrors bool
+
+	// StrictVersionCheck will check an OpenAPI 3+ document for constructs that are not supported by the declared
+	// version, for example 'webhooks' or 'jsonSchemaDialect' in a 3.0 document, or 'nullable' in a 3.1 document.
+	// Each one is added to the document warnings as a *v3.VersionMismatch. This is disabled by default.
+	StrictVersionCheck bool
+
+	// SchemaDialectValidator is called for each schema in 'components' as it is extracted from an OpenAPI 3+
+	// document, with the declared 'jsonSchemaDialect' (empty if there isn't one). Errors returned are added to the
+	// document warnings, unless SchemaDialectErrors is set. When nil, schemas are not validated.
+	SchemaDialectValidator SchemaDialectValidatorFunc
+
+	// SchemaDialectErrors reports errors returned by SchemaDialectValidator as errors instead of warnings.
+	SchemaDialectErrors bool
+
+	// ExtraExtractors are additional extraction functions that run after the built-in extractions when building an
+	// OpenAPI 3+ document, for example to parse a vendor extension into a typed model in the same pass. Errors
+	// returned are joined with the errors returned by the document builder.
+	ExtraExtractors []ExtractorFunc
 }
 
+// ExtractorFunc is a custom extraction function, run while building a low-level OpenAPI 3+ document. The document
+// is the *v3.Document being built, and the index is the root *index.SpecIndex (neither can be referenced directly
+// from this package).
+type ExtractorFunc func(ctx context.Context, info *SpecInfo, document any, index any) error
+
+// SchemaDialectValidatorFunc validates a schema against the dialect declared by a document. The schema is a
+// low-level *base.SchemaProxy (which cannot be referenced directly from this package).
+type SchemaDialectValidatorFunc func(dialect string, schema any) error
+
+// ProgressFunc is a function used to report progress while a document is being built.
+type ProgressFunc func(stage string, done, total int)
+
 func NewDocumentConfiguration() *DocumentConfiguration {
 	return &DocumentConfiguration{
 		Logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
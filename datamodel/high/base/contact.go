@@ -51,3 +51,10 @@ func (c *Contact) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(c, c.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Contact object, using the options of
+// the NodeBuilder rendering it.
+func (c *Contact) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(c, c.low, opts)
+	return nb.Render(), nil
+}
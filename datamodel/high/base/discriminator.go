@@ -55,3 +55,10 @@ func (d *Discriminator) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(d, d.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Discriminator object, using the
+// options of the NodeBuilder rendering it.
+func (d *Discriminator) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(d, d.low, opts)
+	return nb.Render(), nil
+}
@@ -92,6 +92,25 @@ func (d *DynamicValue[A, B]) MarshalYAML() (interface{}, error) {
 	return &n, err
 }
 
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the DynamicValue object, using the
+// options of the NodeBuilder rendering it, if the value can be rendered with options.
+func (d *DynamicValue[A, B]) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	var value any
+	if d.IsA() {
+		value = d.A
+	}
+	if d.IsB() {
+		value = d.B
+	}
+	if r, ok := value.(high.RenderableWithOptions); ok {
+		return r.MarshalYAMLWithOptions(opts)
+	}
+	if opts.Resolve {
+		return d.MarshalYAMLInline()
+	}
+	return d.MarshalYAML()
+}
+
 // MarshalYAMLInline will create a ready to render YAML representation of the DynamicValue object. The
 // references will be inlined instead of kept as references.
 func (d *DynamicValue[A, B]) MarshalYAMLInline() (interface{}, error) {
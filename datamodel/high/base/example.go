@@ -52,12 +52,34 @@ func (e *Example) Render() ([]byte, error) {
 	return yaml.Marshal(e)
 }
 
+// RenderInline will return a YAML representation of the Example object as a byte slice, with all references
+// rendered inline.
+func (e *Example) RenderInline() ([]byte, error) {
+	d, _ := e.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Example object.
 func (e *Example) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(e, e.low)
 	return nb.Render(), nil
 }
 
+// MarshalYAMLInline will create a ready to render YAML representation of the Example object, with all references
+// rendered inline.
+func (e *Example) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(e, e.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Example object, using the options of
+// the NodeBuilder rendering it.
+func (e *Example) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(e, e.low, opts)
+	return nb.Render(), nil
+}
+
 // MarshalJSON will marshal this into a JSON byte slice
 func (e *Example) MarshalJSON() ([]byte, error) {
 	var g map[string]any
@@ -61,3 +61,10 @@ func (e *ExternalDoc) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(e, e.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the ExternalDoc object, using the
+// options of the NodeBuilder rendering it.
+func (e *ExternalDoc) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(e, e.low, opts)
+	return nb.Render(), nil
+}
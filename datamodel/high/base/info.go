@@ -80,3 +80,10 @@ func (i *Info) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(i, i.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Info object, using the options of
+// the NodeBuilder rendering it.
+func (i *Info) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(i, i.low, opts)
+	return nb.Render(), nil
+}
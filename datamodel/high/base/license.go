@@ -59,3 +59,10 @@ func (l *License) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(l, l.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the License object, using the options of
+// the NodeBuilder rendering it.
+func (l *License) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(l, l.low, opts)
+	return nb.Render(), nil
+}
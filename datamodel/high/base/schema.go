@@ -481,31 +481,34 @@ func (s *Schema) RenderInline() ([]byte, error) {
 	return yaml.Marshal(d)
 }
 
+// specVersion returns the version of the specification the schema was read from, or 0 if the schema was not
+// read from a document (for example, a schema created by hand).
+func (s *Schema) specVersion() float32 {
+	if s.low == nil || s.low.Index == nil || s.low.Index.GetConfig().SpecInfo == nil {
+		return 0
+	}
+	return s.low.Index.GetConfig().SpecInfo.VersionNumeric
+}
+
 // MarshalYAML will create a ready to render YAML representation of the ExternalDoc object.
 func (s *Schema) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(s, s.low)
+	nb.Version = s.specVersion()
+	return nb.Render(), nil
+}
 
-	// determine index version
-	idx := s.GoLow().Index
-	if idx != nil {
-		if idx.GetConfig().SpecInfo != nil {
-			nb.Version = idx.GetConfig().SpecInfo.VersionNumeric
-		}
-	}
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Schema object, using the options of
+// the NodeBuilder rendering it.
+func (s *Schema) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
+	nb.Version = s.specVersion()
 	return nb.Render(), nil
 }
 
 // MarshalJSON will create a ready to render JSON representation of the Schema object.
 func (s *Schema) MarshalJSON() ([]byte, error) {
 	nb := high.NewNodeBuilder(s, s.low)
-
-	// determine index version
-	idx := s.GoLow().Index
-	if idx != nil {
-		if idx.GetConfig().SpecInfo != nil {
-			nb.Version = idx.GetConfig().SpecInfo.VersionNumeric
-		}
-	}
+	nb.Version = s.specVersion()
 	// render node
 	node := nb.Render()
 	var renderedJSON map[string]interface{}
@@ -521,13 +524,7 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 func (s *Schema) MarshalYAMLInline() (interface{}, error) {
 	nb := high.NewNodeBuilder(s, s.low)
 	nb.Resolve = true
-	// determine index version
-	idx := s.GoLow().Index
-	if idx != nil {
-		if idx.GetConfig().SpecInfo != nil {
-			nb.Version = idx.GetConfig().SpecInfo.VersionNumeric
-		}
-	}
+	nb.Version = s.specVersion()
 	return nb.Render(), nil
 }
 
@@ -535,13 +532,7 @@ func (s *Schema) MarshalYAMLInline() (interface{}, error) {
 func (s *Schema) MarshalJSONInline() ([]byte, error) {
 	nb := high.NewNodeBuilder(s, s.low)
 	nb.Resolve = true
-	// determine index version
-	idx := s.GoLow().Index
-	if idx != nil {
-		if idx.GetConfig().SpecInfo != nil {
-			nb.Version = idx.GetConfig().SpecInfo.VersionNumeric
-		}
-	}
+	nb.Version = s.specVersion()
 	// render node
 	node := nb.Render()
 	var renderedJSON map[string]interface{}
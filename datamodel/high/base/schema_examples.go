@@ -0,0 +1,16 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+// MigrateExampleToExamples will move a singular `example` value into the `examples` array and clear `example`.
+// In OpenAPI 3.1, the schema `example` keyword is deprecated in favor of `examples`. This is opt-in, nothing is
+// migrated unless this method is called. Returns true if an example was migrated.
+func (s *Schema) MigrateExampleToExamples() bool {
+	if s == nil || s.Example == nil {
+		return false
+	}
+	s.Examples = append(s.Examples, s.Example)
+	s.Example = nil
+	return true
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+// SchemaMetrics holds complexity measurements for a Schema and everything reachable from it.
+type SchemaMetrics struct {
+	// Depth is the maximum nesting depth of the schema. A schema with no sub-schemas has a depth of 1.
+	Depth int `json:"depth" yaml:"depth"`
+
+	// Properties is the number of properties defined directly on the schema.
+	Properties int `json:"properties" yaml:"properties"`
+
+	// TotalProperties is the number of properties defined across the schema and all of its sub-schemas.
+	// Referenced schemas are only counted once, no matter how many times they are referenced.
+	TotalProperties int `json:"totalProperties" yaml:"totalProperties"`
+
+	// ReferenceFanOut is the number of unique references reachable from the schema.
+	ReferenceFanOut int `json:"referenceFanOut" yaml:"referenceFanOut"`
+
+	// Circular is true if a circular reference was found while walking the schema.
+	Circular bool `json:"circular" yaml:"circular"`
+}
+
+// Metrics will walk the schema and all of its sub-schemas and return complexity measurements. Each reference is
+// resolved once, and circular references are detected and not followed.
+func (s *Schema) Metrics() SchemaMetrics {
+	return s.metrics("")
+}
+
+// MetricsForReference works the same way as Metrics, but treats the schema as if it was located at the supplied
+// reference (for example '#/components/schemas/Pet'). This allows a schema that references itself to be
+// detected as circular without being walked twice.
+func (s *Schema) MetricsForReference(reference string) SchemaMetrics {
+	return s.metrics(reference)
+}
+
+func (s *Schema) metrics(reference string) SchemaMetrics {
+	if s == nil {
+		return SchemaMetrics{}
+	}
+	w := &schemaMetricsWalker{
+		depths: make(map[string]int),
+		stack:  make(map[string]bool),
+		refs:   make(map[string]struct{}),
+	}
+	if reference != "" {
+		w.stack[reference] = true
+	}
+	m := SchemaMetrics{Depth: w.walkSchema(s)}
+	if s.Properties != nil {
+		m.Properties = s.Properties.Len()
+	}
+	m.TotalProperties = w.properties
+	m.ReferenceFanOut = len(w.refs)
+	m.Circular = w.circular
+	return m
+}
+
+// schemaMetricsWalker keeps track of state while walking a schema tree.
+type schemaMetricsWalker struct {
+	depths     map[string]int      // depths of references that have already been walked.
+	stack      map[string]bool     // references currently being walked, used to detect cycles.
+	refs       map[string]struct{} // every unique reference seen.
+	properties int
+	circular   bool
+}
+
+func (w *schemaMetricsWalker) walkProxy(sp *SchemaProxy) int {
+	if sp == nil {
+		return 0
+	}
+	if !sp.IsReference() {
+		return w.walkSchema(sp.Schema())
+	}
+	ref := sp.GetReference()
+	w.refs[ref] = struct{}{}
+	if w.stack[ref] {
+		w.circular = true
+		return 0
+	}
+	if d, ok := w.depths[ref]; ok {
+		return d
+	}
+	// a reference created by hand has nothing to resolve.
+	if sp.schema == nil && sp.rendered == nil {
+		w.depths[ref] = 0
+		return 0
+	}
+	w.stack[ref] = true
+	d := w.walkSchema(sp.Schema())
+	delete(w.stack, ref)
+	w.depths[ref] = d
+	return d
+}
+
+func (w *schemaMetricsWalker) walkSchema(s *Schema) int {
+	if s == nil {
+		return 0
+	}
+	var children []*SchemaProxy
+	children = append(children, s.AllOf...)
+	children = append(children, s.OneOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.PrefixItems...)
+	children = append(children, s.Contains, s.If, s.Else, s.Then, s.PropertyNames, s.UnevaluatedItems, s.Not)
+	if s.Items != nil && s.Items.IsA() {
+		children = append(children, s.Items.A)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.IsA() {
+		children = append(children, s.AdditionalProperties.A)
+	}
+	if s.UnevaluatedProperties != nil && s.UnevaluatedProperties.IsA() {
+		children = append(children, s.UnevaluatedProperties.A)
+	}
+	if s.Properties != nil {
+		w.properties += s.Properties.Len()
+		for _, p := range s.Properties.FromOldest() {
+			children = append(children, p)
+		}
+	}
+	if s.PatternProperties != nil {
+		for _, p := range s.PatternProperties.FromOldest() {
+			children = append(children, p)
+		}
+	}
+	if s.DependentSchemas != nil {
+		for _, p := range s.DependentSchemas.FromOldest() {
+			children = append(children, p)
+		}
+	}
+
+	deepest := 0
+	for _, c := range children {
+		if d := w.walkProxy(c); d > deepest {
+			deepest = d
+		}
+	}
+	return deepest + 1
+}
@@ -190,6 +190,15 @@ func (sp *SchemaProxy) Render() ([]byte, error) {
 
 // MarshalYAML will create a ready to render YAML representation of the SchemaProxy object.
 func (sp *SchemaProxy) MarshalYAML() (interface{}, error) {
+	return sp.MarshalYAMLWithOptions(high.RenderOptions{})
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the SchemaProxy object, using the
+// options of the NodeBuilder rendering it. The $ref values are inlined if the options resolve references.
+func (sp *SchemaProxy) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	if opts.Resolve {
+		return sp.marshalYAMLInline(opts)
+	}
 	var s *Schema
 	var err error
 	// if this schema isn't a reference, then build it out.
@@ -198,7 +207,7 @@ func (sp *SchemaProxy) MarshalYAML() (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		nb := high.NewNodeBuilder(s, s.low)
+		nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
 		return nb.Render(), nil
 	} else {
 		refNode := sp.GetReferenceNode()
@@ -214,12 +223,22 @@ func (sp *SchemaProxy) MarshalYAML() (interface{}, error) {
 // MarshalYAMLInline will create a ready to render YAML representation of the SchemaProxy object. The
 // $ref values will be inlined instead of kept as is.
 func (sp *SchemaProxy) MarshalYAMLInline() (interface{}, error) {
+	return sp.marshalYAMLInline(high.RenderOptions{Resolve: true})
+}
+
+// marshalYAMLInline renders the SchemaProxy with $ref values inlined, unless they are circular.
+func (sp *SchemaProxy) marshalYAMLInline(opts high.RenderOptions) (interface{}, error) {
 	var s *Schema
 	var err error
 	s, err = sp.BuildSchema()
 
 	if s != nil && s.GoLow() != nil && s.GoLow().Index != nil {
-		circ := s.GoLow().Index.GetCircularReferences()
+		idx := s.GoLow().Index
+		// ignored circular references cannot be inlined either.
+		var circ []*index.CircularReferenceResult
+		circ = append(circ, idx.GetCircularReferences()...)
+		circ = append(circ, idx.GetIgnoredArrayCircularReferences()...)
+		circ = append(circ, idx.GetIgnoredPolymorphicCircularReferences()...)
 		for _, c := range circ {
 			if sp.IsReference() {
 				// we cannot proceed.
@@ -233,7 +252,7 @@ func (sp *SchemaProxy) MarshalYAMLInline() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	nb := high.NewNodeBuilder(s, s.low)
-	nb.Resolve = true
+	opts.Resolve = true
+	nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
 	return nb.Render(), nil
 }
@@ -10,10 +10,11 @@ import (
 	"testing"
 
 	"github.com/pb33f/libopenapi/datamodel"
-
+	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
 	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -1372,3 +1373,186 @@ func TestSchema_RenderProxyWithConfig_Corrected_3(t *testing.T) {
 	schemaBytes, _ = compiled.RenderInline()
 	assert.Equal(t, testSpecCorrect, strings.TrimSpace(string(schemaBytes)))
 }
+
+func TestNewSchemaProxy_RenderNumberFidelity(t *testing.T) {
+	testSpec := `type: number
+multipleOf: 0.01
+maximum: 9007199254740993
+minimum: 1.0
+`
+
+	var compNode yaml.Node
+	_ = yaml.Unmarshal([]byte(testSpec), &compNode)
+
+	sp := new(lowbase.SchemaProxy)
+	err := sp.Build(context.Background(), nil, compNode.Content[0], nil)
+	assert.NoError(t, err)
+
+	lowproxy := low.NodeReference[*lowbase.SchemaProxy]{
+		Value:     sp,
+		ValueNode: compNode.Content[0],
+	}
+
+	compiled := NewSchemaProxy(&lowproxy).Schema()
+	assert.Equal(t, 0.01, *compiled.MultipleOf)
+
+	// unchanged numbers keep their original representation.
+	schemaBytes, _ := compiled.Render()
+	assert.Equal(t, testSpec, string(schemaBytes))
+
+	// changed numbers use the shortest exact representation.
+	a, b := 0.1, 0.2
+	maximum := a + b
+	multipleOf := 0.000001
+	compiled.Maximum = &maximum
+	compiled.MultipleOf = &multipleOf
+	schemaBytes, _ = compiled.Render()
+	assert.Equal(t, `type: number
+multipleOf: 0.000001
+maximum: 0.30000000000000004
+minimum: 1.0
+`, string(schemaBytes))
+}
+
+func TestSchema_RenderZeroValues(t *testing.T) {
+	testSpec := `type: object
+default: null
+required: []
+properties: {}
+`
+
+	var compNode yaml.Node
+	_ = yaml.Unmarshal([]byte(testSpec), &compNode)
+
+	sp := new(lowbase.SchemaProxy)
+	err := sp.Build(context.Background(), nil, compNode.Content[0], nil)
+	assert.NoError(t, err)
+
+	lowproxy := low.NodeReference[*lowbase.SchemaProxy]{
+		Value:     sp,
+		ValueNode: compNode.Content[0],
+	}
+	compiled := NewSchemaProxy(&lowproxy).Schema()
+
+	nb := high.NewNodeBuilder(compiled, compiled.GoLow())
+	nb.RenderZeroValues = true
+	schemaBytes, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, testSpec, string(schemaBytes))
+}
+
+func TestNewSchemaProxy_RenderSchemaTypes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		rendered string
+		types    []string
+	}{
+		{"single type", "type: string\n", "type: string\n", []string{"string"}},
+		{"nullable type", "type:\n    - string\n    - \"null\"\n", "type:\n    - string\n    - \"null\"\n", []string{"string", "null"}},
+		{"flow sequence", "type: [string, \"null\"]\n", "type:\n    - string\n    - \"null\"\n", []string{"string", "null"}},
+		{"single element sequence", "type: [integer]\n", "type: integer\n", []string{"integer"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var compNode yaml.Node
+			_ = yaml.Unmarshal([]byte(tc.spec), &compNode)
+
+			sp := new(lowbase.SchemaProxy)
+			err := sp.Build(context.Background(), nil, compNode.Content[0], nil)
+			assert.NoError(t, err)
+
+			lowproxy := low.NodeReference[*lowbase.SchemaProxy]{
+				Value:     sp,
+				ValueNode: compNode.Content[0],
+			}
+			compiled := NewSchemaProxy(&lowproxy).Schema()
+			assert.Equal(t, tc.types, compiled.Type)
+
+			schemaBytes, _ := compiled.Render()
+			assert.Equal(t, tc.rendered, string(schemaBytes))
+
+			// rendering the rendered schema again does not change it.
+			_ = yaml.Unmarshal(schemaBytes, &compNode)
+			sp = new(lowbase.SchemaProxy)
+			assert.NoError(t, sp.Build(context.Background(), nil, compNode.Content[0], nil))
+			lowproxy = low.NodeReference[*lowbase.SchemaProxy]{Value: sp, ValueNode: compNode.Content[0]}
+			schemaBytes, _ = NewSchemaProxy(&lowproxy).Schema().Render()
+			assert.Equal(t, tc.rendered, string(schemaBytes))
+		})
+	}
+}
+
+func TestSchema_RenderTypesWithoutLowModel(t *testing.T) {
+	nullable := CreateSchemaProxy(&Schema{Type: []string{"string", "null"}})
+	rendered, _ := nullable.Render()
+	assert.Equal(t, "type:\n    - string\n    - \"null\"\n", string(rendered))
+
+	single := CreateSchemaProxy(&Schema{Type: []string{"string"}})
+	rendered, _ = single.Render()
+	assert.Equal(t, "type: string\n", string(rendered))
+}
+
+func TestSchema_RenderStandalone(t *testing.T) {
+	spec := `description: a lone pet
+type: object
+x-registry: pets
+required:
+    - name
+properties:
+    name:
+        type: string
+x-owner: pets-team
+`
+	var compNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &compNode)
+
+	sp := new(lowbase.SchemaProxy)
+	assert.NoError(t, sp.Build(context.Background(), nil, compNode.Content[0], nil))
+	lowproxy := low.NodeReference[*lowbase.SchemaProxy]{Value: sp, ValueNode: compNode.Content[0]}
+	schema := NewSchemaProxy(&lowproxy).Schema()
+
+	// extensions stay where they are in the source, not grouped together.
+	rendered, err := schema.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, spec, string(rendered))
+
+	// new content is added to the bottom, in the order it was added.
+	schema.Title = "Pet"
+	assert.NoError(t, high.AddExtension(schema, "x-added", true))
+	rendered, err = schema.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, spec+"title: Pet\nx-added: true\n", string(rendered))
+}
+
+func TestSchema_RenderStandaloneWithoutLowModel(t *testing.T) {
+	schema := &Schema{
+		Type:        []string{"object"},
+		Description: "hand built",
+		Properties:  orderedmap.New[string, *SchemaProxy](),
+	}
+	schema.Properties.Set("name", CreateSchemaProxy(&Schema{Type: []string{"string"}}))
+	assert.NoError(t, high.AddExtension(schema, "x-one", 1))
+	assert.NoError(t, high.AddExtension(schema, "x-two", 2))
+	assert.NoError(t, high.AddExtension(schema, "x-three", 3))
+
+	expected := `type: object
+properties:
+    name:
+        type: string
+description: hand built
+x-one: 1
+x-two: 2
+x-three: 3
+`
+	for i := 0; i < 10; i++ {
+		rendered, err := schema.Render()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(rendered))
+	}
+
+	b, err := schema.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","description":"hand built","properties":{"name":{"type":"string"}},
+"x-one":1,"x-two":2,"x-three":3}`, string(b))
+}
@@ -92,7 +92,7 @@ func (s *SecurityRequirement) MarshalYAML() (interface{}, error) {
 			}
 		}
 	}
-	sort.Slice(keys, func(i, j int) bool {
+	sort.SliceStable(keys, func(i, j int) bool {
 		return keys[i].line < keys[j].line
 	})
 
@@ -120,7 +120,7 @@ func (s *SecurityRequirement) MarshalYAML() (interface{}, error) {
 				}
 			}
 		}
-		sort.Slice(reqs, func(i, j int) bool {
+		sort.SliceStable(reqs, func(i, j int) bool {
 			return reqs[i].line < reqs[j].line
 		})
 		sn := utils.CreateEmptySequenceNode()
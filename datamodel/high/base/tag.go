@@ -73,3 +73,10 @@ func (t *Tag) MarshalYAMLInline() (interface{}, error) {
 	nb.Resolve = true
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Tag object, using the options of
+// the NodeBuilder rendering it.
+func (t *Tag) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(t, t.low, opts)
+	return nb.Render(), nil
+}
@@ -63,3 +63,10 @@ func (x *XML) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(x, x.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the XML object, using the options of
+// the NodeBuilder rendering it.
+func (x *XML) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(x, x.low, opts)
+	return nb.Render(), nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+var extensionsType = reflect.TypeOf((*orderedmap.Map[string, *yaml.Node])(nil))
+
+// Extension is a single extension key and value, as returned by OrderedExtensions.
+type Extension struct {
+	Key   string
+	Value *yaml.Node
+}
+
+// AddExtension adds an extension to a high-level model (any model with an Extensions field), creating the
+// extensions map if required. value can be a *yaml.Node, or anything that can be encoded as YAML. A new
+// extension is added after all existing extensions, and an existing extension is replaced in place, so
+// extensions are kept (and rendered) in the order they were added.
+//
+//	err := AddExtension(operation, "x-ratelimit", RateLimit{Limit: 10})
+func AddExtension(obj any, key string, value any) error {
+	if !strings.HasPrefix(key, "x-") {
+		return fmt.Errorf("unable to add extension '%s', extensions must start with 'x-'", key)
+	}
+	field, err := extensionsField(obj)
+	if err != nil {
+		return err
+	}
+	node, ok := value.(*yaml.Node)
+	if !ok {
+		node = new(yaml.Node)
+		if err = node.Encode(value); err != nil {
+			return fmt.Errorf("unable to encode extension '%s': %w", key, err)
+		}
+	}
+	if field.IsNil() {
+		field.Set(reflect.ValueOf(orderedmap.New[string, *yaml.Node]()))
+	}
+	field.Interface().(*orderedmap.Map[string, *yaml.Node]).Set(key, node)
+	return nil
+}
+
+// OrderedExtensions returns the extensions of a high-level model as a slice, in the order they were defined
+// or added. Nil is returned if obj has no extensions.
+func OrderedExtensions(obj any) []Extension {
+	field, err := extensionsField(obj)
+	if err != nil || field.IsNil() {
+		return nil
+	}
+	var extensions []Extension
+	for k, v := range field.Interface().(*orderedmap.Map[string, *yaml.Node]).FromOldest() {
+		extensions = append(extensions, Extension{Key: k, Value: v})
+	}
+	return extensions
+}
+
+func extensionsField(obj any) (reflect.Value, error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("unable to find extensions, '%T' is not a high-level model", obj)
+	}
+	field := v.Elem().FieldByName("Extensions")
+	if !field.IsValid() || field.Type() != extensionsType {
+		return reflect.Value{}, fmt.Errorf("unable to find extensions, '%T' does not support extensions", obj)
+	}
+	return field, nil
+}
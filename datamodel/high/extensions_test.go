@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type extended struct {
+	Name       string                              `yaml:"name,omitempty"`
+	Extensions *orderedmap.Map[string, *yaml.Node] `yaml:"-"`
+}
+
+func TestAddExtension(t *testing.T) {
+	e := &extended{Name: "pizza"}
+
+	require.NoError(t, AddExtension(e, "x-cheese", "mozzarella"))
+	require.NoError(t, AddExtension(e, "x-slices", 8))
+	require.NoError(t, AddExtension(e, "x-toppings", []string{"basil", "tomato"}))
+	require.NoError(t, AddExtension(e, "x-oven", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "wood"}))
+
+	// replacing an extension keeps its position.
+	require.NoError(t, AddExtension(e, "x-slices", 6))
+
+	var keys []string
+	for _, ext := range OrderedExtensions(e) {
+		keys = append(keys, ext.Key)
+	}
+	assert.Equal(t, []string{"x-cheese", "x-slices", "x-toppings", "x-oven"}, keys)
+	assert.Equal(t, "6", OrderedExtensions(e)[1].Value.Value)
+
+	expected := `name: pizza
+x-cheese: mozzarella
+x-slices: 6
+x-toppings:
+    - basil
+    - tomato
+x-oven: wood
+`
+	for i := 0; i < 10; i++ {
+		b, err := yaml.Marshal(NewNodeBuilder(e, nil).Render())
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+	}
+}
+
+func TestAddExtension_Errors(t *testing.T) {
+	e := &extended{}
+	assert.EqualError(t, AddExtension(e, "cheese", "cheddar"),
+		"unable to add extension 'cheese', extensions must start with 'x-'")
+	assert.EqualError(t, AddExtension(e, "x-bad", badExtension{}),
+		"unable to encode extension 'x-bad': burnt")
+	assert.EqualError(t, AddExtension(plug{}, "x-cheese", "cheddar"),
+		"unable to find extensions, 'high.plug' is not a high-level model")
+	assert.EqualError(t, AddExtension(&plug{}, "x-cheese", "cheddar"),
+		"unable to find extensions, '*high.plug' does not support extensions")
+	assert.Nil(t, e.Extensions)
+}
+
+func TestOrderedExtensions_None(t *testing.T) {
+	assert.Nil(t, OrderedExtensions(&extended{}))
+	assert.Nil(t, OrderedExtensions(nil))
+	assert.Nil(t, OrderedExtensions(&plug{}))
+}
+
+type badExtension struct{}
+
+func (badExtension) MarshalYAML() (any, error) {
+	return nil, errors.New("burnt")
+}
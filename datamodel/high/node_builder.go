@@ -4,6 +4,8 @@
 package high
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
@@ -27,17 +29,100 @@ type NodeBuilder struct {
 	High    any
 	Low     any
 	Resolve bool // If set to true, all references will be rendered inline
+
+	// Errors contains any errors that were encountered while rendering, fields that could not be rendered are
+	// skipped, and the reason is recorded here, rather than failing the entire render. Errors from nested objects
+	// that implement RenderableWithOptions are included.
+	Errors []error
+
+	// Comments is a map of comments that will be rendered as head comments above the matching key. Keys can be
+	// either the rendered tag name (e.g. 'paths') or the name of the field (e.g. 'Paths') of the root object, or
+	// the path to a nested key, with keys separated by a '.' (e.g. 'paths./pets.get' or 'servers.0.url'). Paths
+	// reach nested objects that implement RenderableWithOptions.
+	Comments map[string]string
+
+	// Marshalers is a map of custom marshalers that render a value instead of the built-in rendering. Keys can be
+	// either the rendered tag name (e.g. 'x-created' or 'description') or the name of the field (e.g.
+	// 'Description'). The marshaler is passed the high-level value, and returns the node to render, a nil node
+	// leaves the field out, and an error is recorded in Errors. Fields with no marshaler render as usual. The
+	// marshalers carry over to nested objects that implement RenderableWithOptions, so a key matches at every level.
+	Marshalers map[string]func(any) (*yaml.Node, error)
+
+	// AlwaysEmitKeys is a list of keys (rendered tag names, e.g. 'components') that will always be rendered, even
+	// when they are empty. Empty keys are rendered as an empty map (or an empty sequence for slices), for example
+	// 'components: {}'. All other empty values are still omitted.
+	AlwaysEmitKeys []string
+
+	// SkipField is consulted for every field as the NodeBuilder is created, fields with a rendered tag name
+	// (e.g. 'servers') or an extension key (e.g. 'x-internal') that it returns true for are never rendered.
+	// Use NewNodeBuilderWithFilter to set it, because fields are added when the NodeBuilder is created. The filter
+	// carries over to nested objects that implement RenderableWithOptions.
+	SkipField func(key string) bool
+
+	// RenderZeroValues will render fields with zero values (empty strings, false, zero, null, and empty sequences
+	// or maps) that were present in the original document, for example 'security: []' which means 'no security'.
+	// Explicit nulls are rendered as 'null'. Zero values with no original low-level node are still omitted. The
+	// option carries over to nested objects that implement RenderableWithOptions.
+	RenderZeroValues bool
+
+	// PreserveAliases will restore the YAML anchors and aliases used in the original document, instead of rendering
+	// every alias expanded. The first copy of an anchored value is rendered with the anchor, and later copies are
+	// rendered as aliases, unless they have been changed. The low-level object must implement low.HasRootNode.
+	PreserveAliases bool
+
+	// MinimalDiff will re-use the original node from the source document, for every value that has not been
+	// changed, so the styles, comments and formatting of untouched content are kept exactly, and only changed or new
+	// values are rendered fresh. This keeps the textual diff of a changed document as small as possible. The
+	// low-level object must implement low.HasRootNode.
+	MinimalDiff bool
+
+	// TrackLines will build a map of original line numbers to rendered line numbers as the node is rendered, which
+	// is returned by LineMap. The low-level object must implement low.HasRootNode.
+	TrackLines bool
+
+	// SortMode controls the order keys are rendered in, SortByLine (the default) keeps the order of the original
+	// document, SortAlphabetical sorts the keys of every map, which gives a stable order to generated documents.
+	SortMode SortMode
+
+	lineMap map[int]int // original line numbers to rendered line numbers, built when TrackLines is set.
+
+	zeroValues []*nodes.NodeEntry // zero values that were present in the original document.
+
+	errorSink *[]error // the Errors of the NodeBuilder rendering the root object, when rendering a nested object.
+	path      string   // the path of the object being rendered from the root object, empty for the root object.
 }
 
 const renderZero = "renderZero"
 
 // NewNodeBuilder will create a new NodeBuilder instance, this is the only way to create a NodeBuilder.
-// The function accepts a high level object and a low level object (need to be siblings/same type).
+// The function accepts a high level object and a low level object (need to be siblings/same type). The low level
+// object can be nil (for example, an object created by hand), fields are then rendered in the order they are
+// defined, with extensions in the order they were added.
 //
 // Using reflection, a map of every field in the high level object is created, ready to be rendered.
 func NewNodeBuilder(high any, low any) *NodeBuilder {
+	return NewNodeBuilderWithFilter(high, low, nil)
+}
+
+// NewNodeBuilderWithOptions works the same way as NewNodeBuilder, with the options of a parent NodeBuilder applied,
+// so nested objects are rendered the same way as the object that contains them.
+func NewNodeBuilderWithOptions(high any, low any, opts RenderOptions) *NodeBuilder {
+	nb := NewNodeBuilderWithFilter(high, low, opts.SkipField)
+	nb.Resolve = opts.Resolve
+	nb.RenderZeroValues = opts.RenderZeroValues
+	nb.Marshalers = opts.Marshalers
+	nb.Comments = opts.Comments
+	nb.errorSink = opts.errors
+	nb.path = opts.path
+	return nb
+}
+
+// NewNodeBuilderWithFilter works the same way as NewNodeBuilder, but any field or extension that skip returns
+// true for is left out of the rendered output. The high and low level objects are not modified.
+func NewNodeBuilderWithFilter(high any, low any, skip func(key string) bool) *NodeBuilder {
 	// create a new node builder
 	nb := new(NodeBuilder)
+	nb.SkipField = skip
 	nb.High = high
 	if low != nil {
 		nb.Low = low
@@ -75,25 +160,29 @@ func (n *NodeBuilder) add(key string, i int) {
 			}
 		}
 
-		j := 0
-		if lowExtensions != nil {
-			// If we have low extensions get the original lowest line number so we end up in the same place
-			for ext := range lowExtensions.KeysFromOldest() {
-				if j == 0 || ext.KeyNode.Line < j {
-					j = ext.KeyNode.Line
-				}
-			}
+		// without a low-level sibling there are no line numbers, so extensions are kept in place (in the order
+		// they were added), otherwise extensions that are not in the original document are weighted to the bottom.
+		line := 0
+		if n.Low != nil && !reflect.ValueOf(n.Low).IsZero() {
+			line = 9999 + i
 		}
-
 		for ext, node := range extensions.FromOldest() {
-			nodeEntry := &nodes.NodeEntry{Tag: ext, Key: ext, Value: node, Line: j}
+			if n.skip(ext) {
+				continue
+			}
+			nodeEntry := &nodes.NodeEntry{Tag: ext, Key: ext, Value: node, Line: line}
 
 			if lowExtensions != nil {
-				lowItem := low.FindItemInOrderedMap(ext, lowExtensions)
+				lowKey, lowItem := low.FindItemInOrderedMapWithKey(ext, lowExtensions)
 				nodeEntry.LowValue = lowItem
+				if lowKey != nil && lowKey.KeyNode != nil {
+					nodeEntry.KeyNode = lowKey.KeyNode
+					if lowKey.KeyNode.Line > 0 {
+						nodeEntry.Line = lowKey.KeyNode.Line
+					}
+				}
 			}
 			n.Nodes = append(n.Nodes, nodeEntry)
-			j++
 		}
 		// done, extensions are handled separately.
 		return
@@ -104,11 +193,11 @@ func (n *NodeBuilder) add(key string, i int) {
 	tag := string(field.Tag.Get("yaml"))
 	tagName := strings.Split(tag, ",")[0]
 
-	if tag == "-" {
+	if tag == "-" || n.skip(tagName) {
 		return
 	}
 
-	var renderZeroFlag, omitEmptyFlag bool
+	var renderZeroFlag, omitEmptyFlag, flowFlag bool
 	tagParts := strings.Split(tag, ",")
 	for _, part := range tagParts {
 		if part == renderZero {
@@ -117,6 +206,9 @@ func (n *NodeBuilder) add(key string, i int) {
 		if part == "omitempty" {
 			omitEmptyFlag = true
 		}
+		if part == "flow" {
+			flowFlag = true
+		}
 	}
 
 	// extract the value of the field
@@ -131,18 +223,24 @@ func (n *NodeBuilder) add(key string, i int) {
 	} else if f == nil || value.IsZero() {
 		isZero = true
 	}
-	if !renderZeroFlag && isZero || omitEmptyFlag && isZero {
+	// a false boolean is only rendered if it was present in the original document.
+	explicitFalse := value.Kind() == reflect.Bool && n.lowValuePresent(key)
+	if (!renderZeroFlag && isZero || omitEmptyFlag && isZero) && !explicitFalse {
+		n.addZeroValue(key, tagName)
 		return
 	}
 
 	// create a new node entry
 	nodeEntry := &nodes.NodeEntry{Tag: tagName, Key: key}
 	nodeEntry.RenderZero = renderZeroFlag
+	if flowFlag {
+		nodeEntry.ValueStyle = yaml.FlowStyle
+	}
 	switch value.Kind() {
 	case reflect.Float64, reflect.Float32:
 		nodeEntry.Value = value.Float()
-		x := float64(int(value.Float()*100)) / 100 // trim this down
-		nodeEntry.StringValue = strconv.FormatFloat(x, 'f', -1, 64)
+		// shortest representation that round-trips exactly.
+		nodeEntry.StringValue = strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		nodeEntry.Value = value.Int()
 		nodeEntry.StringValue = value.String()
@@ -193,7 +291,9 @@ func (n *NodeBuilder) add(key string, i int) {
 			sort.Slice(lines, func(i, j int) bool {
 				return lines[i] < lines[j]
 			})
-			if len(lines) > 0 {
+			// a slice that is not in the original document is new content, weight it to the bottom.
+			nodeEntry.Line = 9999 + i
+			if len(lines) > 0 && lines[0] > 0 {
 				nodeEntry.Line = lines[0]
 			}
 		case reflect.Struct:
@@ -221,6 +321,82 @@ func (n *NodeBuilder) add(key string, i int) {
 	}
 }
 
+// addZeroValue keeps a copy of the original value node for a field with a zero value, if the original value is
+// also empty, so it can be rendered when RenderZeroValues is set. A field that has been emptied (the original
+// value was not empty) is not kept.
+func (n *NodeBuilder) addZeroValue(key, tagName string) {
+	if n.Low == nil || reflect.ValueOf(n.Low).IsZero() {
+		return
+	}
+	lowField := reflect.ValueOf(n.Low).Elem().FieldByName(key)
+	if !lowField.IsValid() || !lowField.CanInterface() {
+		return
+	}
+	if lowField.Kind() == reflect.Ptr && lowField.IsNil() {
+		return
+	}
+	fLow := lowField.Interface()
+	hvn, ok := fLow.(low.HasValueNodeUntyped)
+	if !ok {
+		return
+	}
+	vn := hvn.GetValueNode()
+	if vn == nil || !isEmptyNode(vn) {
+		return
+	}
+	value := *vn
+	n.zeroValues = append(n.zeroValues, &nodes.NodeEntry{
+		Tag:      tagName,
+		Key:      key,
+		Value:    &value,
+		Line:     vn.Line,
+		LowValue: fLow,
+	})
+}
+
+// lowValuePresent returns true if the low-level field has a value node, which means it was present in the
+// original document.
+func (n *NodeBuilder) lowValuePresent(key string) bool {
+	if n.Low == nil || reflect.ValueOf(n.Low).IsZero() {
+		return false
+	}
+	lowField := reflect.ValueOf(n.Low).Elem().FieldByName(key)
+	if !lowField.IsValid() || !lowField.CanInterface() {
+		return false
+	}
+	if lowField.Kind() == reflect.Ptr && lowField.IsNil() {
+		return false
+	}
+	hvn, ok := lowField.Interface().(low.HasValueNodeUntyped)
+	return ok && hvn.GetValueNode() != nil
+}
+
+// isEmptyNode returns true if the node is null, an empty string, false, zero or an empty sequence or map.
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return true
+		case "!!str":
+			return node.Value == ""
+		case "!!bool":
+			return strings.EqualFold(node.Value, "false")
+		case "!!int", "!!float":
+			f, err := strconv.ParseFloat(node.Value, 64)
+			return err == nil && f == 0
+		}
+	}
+	return false
+}
+
+// skip returns true if the key has been filtered out by SkipField.
+func (n *NodeBuilder) skip(key string) bool {
+	return n.SkipField != nil && n.SkipField(key)
+}
+
 func (n *NodeBuilder) renderReference(fg low.IsReferenced) *yaml.Node {
 	origNode := fg.GetReferenceNode()
 	if origNode == nil {
@@ -232,8 +408,14 @@ func (n *NodeBuilder) renderReference(fg low.IsReferenced) *yaml.Node {
 
 // Render will render the NodeBuilder back to a YAML node, iterating over every NodeEntry defined
 func (n *NodeBuilder) Render() *yaml.Node {
-	if len(n.Nodes) == 0 {
-		return utils.CreateEmptyMapNode()
+	entries := n.Nodes
+	if n.RenderZeroValues && len(n.zeroValues) > 0 {
+		entries = append(append([]*nodes.NodeEntry{}, n.Nodes...), n.zeroValues...)
+	}
+	if len(entries) == 0 {
+		m := utils.CreateEmptyMapNode()
+		n.emitEmptyKeys(m)
+		return m
 	}
 
 	// order nodes by line number, retain original order
@@ -247,20 +429,180 @@ func (n *NodeBuilder) Render() *yaml.Node {
 		}
 	}
 
-	sort.Slice(n.Nodes, func(i, j int) bool {
-		if n.Nodes[i].Line != n.Nodes[j].Line {
-			return n.Nodes[i].Line < n.Nodes[j].Line
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Line != entries[j].Line {
+			return entries[i].Line < entries[j].Line
 		}
 		return false
 	})
 
-	for i := range n.Nodes {
-		node := n.Nodes[i]
+	for i := range entries {
+		node := entries[i]
 		n.AddYAMLNode(m, node)
 	}
+	n.emitEmptyKeys(m)
+	if n.MinimalDiff {
+		if rn, ok := n.Low.(low.HasRootNode); ok && !reflect.ValueOf(rn).IsNil() {
+			m = reuseUnchanged(m, rn.GetRootNode())
+		}
+	}
+	if n.SortMode == SortAlphabetical {
+		m = sortKeys(m, true)
+	}
+	if n.PreserveAliases {
+		if rn, ok := n.Low.(low.HasRootNode); ok && !reflect.ValueOf(rn).IsNil() {
+			m = restoreAliases(m, rn.GetRootNode())
+		}
+	}
+	if n.TrackLines {
+		if rn, ok := n.Low.(low.HasRootNode); ok && !reflect.ValueOf(rn).IsNil() {
+			n.buildLineMap(m, rn.GetRootNode())
+		}
+	}
 	return m
 }
 
+// emitEmptyKeys will add an empty value for any key in AlwaysEmitKeys that was not rendered.
+func (n *NodeBuilder) emitEmptyKeys(m *yaml.Node) {
+	for _, key := range n.AlwaysEmitKeys {
+		found := false
+		for i := 0; i < len(m.Content); i += 2 {
+			if m.Content[i].Value == key {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		value := utils.CreateEmptyMapNode()
+		if n.High != nil {
+			t := reflect.TypeOf(n.High)
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct {
+				for i := 0; i < t.NumField(); i++ {
+					if strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0] == key && t.Field(i).Type.Kind() == reflect.Slice {
+						value = utils.CreateEmptySequenceNode()
+						break
+					}
+				}
+			}
+		}
+		k := utils.CreateStringNode(key)
+		if comment, ok := n.Comments[key]; ok {
+			k.HeadComment = comment
+		}
+		m.Content = append(m.Content, k, value)
+	}
+}
+
+// RenderJSON will render the NodeBuilder as JSON, using the same line-number ordering as Render, so the order
+// of keys (including extensions) matches the source document. Scalars are written using their YAML tags, which means
+// booleans and numbers keep their original representation rather than being re-quoted as strings, or being re-parsed
+// into a float64 (which would lose precision).
+//
+// If indention is empty, compact JSON is returned.
+func (n *NodeBuilder) RenderJSON(indention string) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := writeJSONNode(&buf, n.Render()); err != nil {
+		return nil, err
+	}
+	if indention == "" {
+		return buf.Bytes(), nil
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", indention); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	if node == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSONNode(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(node.Content[i].Value)
+			buf.Write(k)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, c := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, c); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		return writeJSONScalar(buf, node)
+	default:
+		return fmt.Errorf("unable to render node kind '%d' as JSON", node.Kind)
+	}
+	return nil
+}
+
+func writeJSONScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+		return nil
+	case "!!int", "!!float":
+		// keep the original text if it's already a valid JSON number, this retains precision.
+		if _, err := strconv.ParseFloat(node.Value, 64); err == nil && json.Valid([]byte(node.Value)) {
+			buf.WriteString(node.Value)
+			return nil
+		}
+		var v any
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			v = node.Value
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+	b, _ := json.Marshal(node.Value)
+	buf.Write(b)
+	return nil
+}
+
 // AddYAMLNode will add a new *yaml.Node to the parent node, using the tag, key and value provided.
 // If the value is nil, then the node will not be added. This method is recursive, so it will dig down
 // into any non-scalar types.
@@ -275,11 +617,22 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 	if entry.Tag != "" {
 		l = utils.CreateStringNode(entry.Tag)
 		l.Style = entry.KeyStyle
+		l.HeadComment = n.findComment(entry)
 	}
 
 	value := entry.Value
 	line := entry.Line
 
+	// a custom marshaler replaces all the built-in rendering for the field.
+	if marshal := n.findMarshaler(entry); marshal != nil {
+		valueNode, err := marshal(value)
+		if err != nil {
+			n.recordError(entry, err)
+			return parent
+		}
+		return n.appendValue(parent, entry, l, valueNode)
+	}
+
 	var valueNode *yaml.Node
 	switch t.Kind() {
 
@@ -288,14 +641,25 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 		valueNode = utils.CreateStringNode(val)
 		valueNode.Line = line
 
+		var vn *yaml.Node
 		if entry.LowValue != nil {
 			if vnut, ok := entry.LowValue.(low.HasValueNodeUntyped); ok {
-				vn := vnut.GetValueNode()
+				vn = vnut.GetValueNode()
 				if vn != nil {
 					valueNode.Style = vn.Style
+					// integers held as strings (like integers too big for int64) keep their original tag.
+					if vn.Kind == yaml.ScalarNode && vn.Value == val && (vn.Tag == "!!int" || vn.Tag == "!!float") &&
+						isIntegerLiteral(val) {
+						valueNode.Tag = vn.Tag
+					}
 				}
 			}
 		}
+		// new or changed multi-line strings are rendered as literal blocks, unless they are already block scalars.
+		if strings.Contains(val, "\n") && (vn == nil || vn.Value != val) &&
+			valueNode.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			valueNode.Style = yaml.LiteralStyle
+		}
 	case reflect.Bool:
 		val := value.(bool)
 		if !val {
@@ -313,22 +677,24 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 		valueNode = utils.CreateIntNode(val)
 		valueNode.Line = line
 	case reflect.Float32:
-		val := strconv.FormatFloat(float64(value.(float32)), 'f', 2, 64)
+		val := strconv.FormatFloat(float64(value.(float32)), 'f', -1, 32)
 		valueNode = utils.CreateFloatNode(val)
 		valueNode.Line = line
 	case reflect.Float64:
-		precision := -1
-		if entry.StringValue != "" && strings.Contains(entry.StringValue, ".") {
-			precision = len(strings.Split(fmt.Sprint(entry.StringValue), ".")[1])
-			val := strconv.FormatFloat(value.(float64), 'f', precision, 64)
-			valueNode = utils.CreateFloatNode(val)
-		} else {
-			val := strconv.FormatFloat(value.(float64), 'f', precision, 64)
-			valueNode = utils.CreateIntNode(val)
+		valueNode = originalNumber(entry, value.(float64))
+		if valueNode == nil {
+			val := entry.StringValue
+			if val == "" {
+				val = strconv.FormatFloat(value.(float64), 'f', -1, 64)
+			}
+			if strings.Contains(val, ".") {
+				valueNode = utils.CreateFloatNode(val)
+			} else {
+				valueNode = utils.CreateIntNode(val)
+			}
 		}
 		valueNode.Line = line
 	case reflect.Slice:
-		var rawNode yaml.Node
 		m := reflect.ValueOf(value)
 		sl := utils.CreateEmptySequenceNode()
 		skip := false
@@ -356,17 +722,7 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 			}
 			if !skip {
 				if er, ko := sqi.(Renderable); ko {
-					var rend interface{}
-					if !n.Resolve {
-						rend, _ = er.MarshalYAML()
-					} else {
-						// try and render inline, if we can, otherwise treat as normal.
-						if _, ko := er.(RenderableInline); ko {
-							rend, _ = er.(RenderableInline).MarshalYAMLInline()
-						} else {
-							rend, _ = er.MarshalYAML()
-						}
-					}
+					rend := n.renderRaw(er, entry.Tag+"."+strconv.Itoa(i))
 					// check if this is a pointer or not.
 					if _, ok := rend.(*yaml.Node); ok {
 						sl.Content = append(sl.Content, rend.(*yaml.Node))
@@ -387,40 +743,41 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 			break
 		}
 
-		err := rawNode.Encode(value)
+		rawNode, err := encodeSequence(m)
 		if err != nil {
+			n.recordError(entry, err)
 			return parent
-		} else {
-			if entry.LowValue != nil {
-				if vnut, ok := entry.LowValue.(low.HasValueNodeUntyped); ok {
-					vn := vnut.GetValueNode()
-					if vn != nil && vn.Kind == yaml.SequenceNode {
-						for i := range vn.Content {
-							if len(rawNode.Content) > i {
-								rawNode.Content[i].Style = vn.Content[i].Style
-							}
+		}
+		rawNode.Style = entry.ValueStyle
+		if entry.LowValue != nil {
+			if vnut, ok := entry.LowValue.(low.HasValueNodeUntyped); ok {
+				vn := vnut.GetValueNode()
+				if vn != nil && vn.Kind == yaml.SequenceNode {
+					for i := range vn.Content {
+						if len(rawNode.Content) > i {
+							rawNode.Content[i].Style = vn.Content[i].Style
 						}
 					}
 				}
 			}
-
-			valueNode = &rawNode
 		}
+		valueNode = rawNode
 
 	case reflect.Struct:
-		if r, ok := value.(low.ValueReference[any]); ok {
-			valueNode = r.GetValueNode()
-			break
-		}
-		if r, ok := value.(low.ValueReference[string]); ok {
-			valueNode = r.GetValueNode()
-			break
+		// structs are rendered the same way as pointers, MarshalYAML first, then value references, then encoded.
+		if r := renderableStruct(value); r != nil {
+			if valueNode = n.marshalRenderable(r, entry.Tag); valueNode != nil {
+				break
+			}
 		}
-		if r, ok := value.(low.NodeReference[string]); ok {
+		if r, ok := value.(hasValueNode); ok && r.GetValueNode() != nil {
 			valueNode = r.GetValueNode()
 			break
 		}
-		return parent
+		valueNode = n.encodeValue(entry, value)
+
+	case reflect.Map:
+		valueNode = n.encodeValue(entry, value)
 
 	case reflect.Ptr:
 		if m, ok := value.(orderedmap.MapToYamlNoder); ok {
@@ -432,7 +789,11 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 				}
 			}
 
+			// map entries are rendered by this NodeBuilder, below the path of the map.
+			parentPath := n.path
+			n.path = joinRenderPath(n.path, entry.Tag)
 			p := m.ToYamlNode(n, l)
+			n.path = parentPath
 			if p.Content != nil {
 				valueNode = p
 			}
@@ -452,28 +813,9 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 					}
 				}
 			}
-			var rawRender interface{}
-			if !n.Resolve {
-				rawRender, _ = r.MarshalYAML()
-			} else {
-				// try an inline render if we can, otherwise there is no option but to default to the
-				// full render.
-
-				if _, ko := r.(RenderableInline); ko {
-					rawRender, _ = r.(RenderableInline).MarshalYAMLInline()
-				} else {
-					rawRender, _ = r.MarshalYAML()
-				}
-			}
-			if rawRender != nil {
-				if _, ko := rawRender.(*yaml.Node); ko {
-					valueNode = rawRender.(*yaml.Node)
-				}
-				if _, ko := rawRender.(yaml.Node); ko {
-					d := rawRender.(yaml.Node)
-					valueNode = &d
-				}
-			}
+			valueNode = n.marshalRenderable(r, entry.Tag)
+		} else if r, ok := value.(hasValueNode); ok && r.GetValueNode() != nil {
+			valueNode = r.GetValueNode()
 		} else {
 
 			encodeSkip := false
@@ -484,7 +826,7 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 					valueNode = utils.CreateBoolNode("true")
 					valueNode.Line = line
 				} else {
-					if entry.RenderZero {
+					if entry.RenderZero || lowValueNode(entry) != nil {
 						valueNode = utils.CreateBoolNode("false")
 						valueNode.Line = line
 					}
@@ -501,14 +843,16 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 				encodeSkip = true
 				if *b > 0 || (entry.RenderZero && entry.Line > 0) {
 					formatFloat := strconv.FormatFloat(*b, 'f', -1, 64)
-					if *b > 0 {
-						if *b == math.Trunc(*b) {
-							valueNode = utils.CreateIntNode(formatFloat)
+					if valueNode = originalNumber(entry, *b); valueNode == nil {
+						if *b > 0 {
+							if *b == math.Trunc(*b) {
+								valueNode = utils.CreateIntNode(formatFloat)
+							} else {
+								valueNode = utils.CreateFloatNode(formatFloat)
+							}
 						} else {
-							valueNode = utils.CreateFloatNode(formatFloat)
+							valueNode = utils.CreateIntNode(formatFloat)
 						}
-					} else {
-						valueNode = utils.CreateIntNode(formatFloat)
 					}
 					valueNode.Line = line
 				}
@@ -516,6 +860,9 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 			if b, bok := value.(*yaml.Node); bok && b.Kind == yaml.ScalarNode && b.Tag == "!!null" {
 				encodeSkip = true
 				valueNode = utils.CreateEmptyScalarNode()
+				if n.RenderZeroValues {
+					valueNode.Value = "null"
+				}
 				valueNode.Line = line
 			}
 			if !encodeSkip {
@@ -530,6 +877,7 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 
 					err := rawNode.Encode(value)
 					if err != nil {
+						n.recordError(entry, err)
 						return parent
 					} else {
 						valueNode = &rawNode
@@ -540,10 +888,17 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 		}
 
 	}
+	return n.appendValue(parent, entry, l, valueNode)
+}
+
+// appendValue adds the rendered key and value to the parent, if there is a key, otherwise the value replaces the
+// content of the parent. Nothing is added if there is no value.
+func (n *NodeBuilder) appendValue(parent *yaml.Node, entry *nodes.NodeEntry, l, valueNode *yaml.Node) *yaml.Node {
 	if valueNode == nil {
 		return parent
 	}
 	if l != nil {
+		n.copyComments(entry, l, valueNode)
 		parent.Content = append(parent.Content, l, valueNode)
 	} else {
 		parent.Content = valueNode.Content
@@ -551,11 +906,282 @@ func (n *NodeBuilder) AddYAMLNode(parent *yaml.Node, entry *nodes.NodeEntry) *ya
 	return parent
 }
 
+// hasValueNode is implemented by value references, that hold the original node of a value.
+type hasValueNode interface {
+	GetValueNode() *yaml.Node
+}
+
+// renderableStruct returns the struct value as a Renderable, if it (or a pointer to it) implements MarshalYAML.
+func renderableStruct(value any) Renderable {
+	if r, ok := value.(Renderable); ok {
+		return r
+	}
+	ptr := reflect.New(reflect.TypeOf(value))
+	ptr.Elem().Set(reflect.ValueOf(value))
+	if r, ok := ptr.Interface().(Renderable); ok {
+		return r
+	}
+	return nil
+}
+
+// renderOptions returns the options of the NodeBuilder that carry over to the nested object found under key.
+func (n *NodeBuilder) renderOptions(key string) RenderOptions {
+	sink := n.errorSink
+	if sink == nil {
+		sink = &n.Errors
+	}
+	return RenderOptions{Resolve: n.Resolve, RenderZeroValues: n.RenderZeroValues, SkipField: n.SkipField,
+		Marshalers: n.Marshalers, Comments: n.Comments, errors: sink, path: joinRenderPath(n.path, key)}
+}
+
+// renderRaw renders a value (found under key) using MarshalYAMLWithOptions, so the options of the NodeBuilder
+// carry over, if it has one, otherwise MarshalYAML (or MarshalYAMLInline when resolving, if it has one) is used.
+func (n *NodeBuilder) renderRaw(r Renderable, key string) any {
+	var rawRender any
+	if ro, ok := r.(RenderableWithOptions); ok {
+		rawRender, _ = ro.MarshalYAMLWithOptions(n.renderOptions(key))
+	} else if ri, ok := r.(RenderableInline); ok && n.Resolve {
+		// try an inline render if we can, otherwise there is no option but to default to the full render.
+		rawRender, _ = ri.MarshalYAMLInline()
+	} else {
+		rawRender, _ = r.MarshalYAML()
+	}
+	return rawRender
+}
+
+// marshalRenderable renders a value (found under key) using renderRaw. Values that don't render to a node are
+// encoded, nil is returned if nothing was rendered.
+func (n *NodeBuilder) marshalRenderable(r Renderable, key string) *yaml.Node {
+	rawRender := n.renderRaw(r, key)
+	switch v := rawRender.(type) {
+	case nil:
+		return nil
+	case *yaml.Node:
+		return v
+	case yaml.Node:
+		return &v
+	}
+	var rawNode yaml.Node
+	if err := rawNode.Encode(rawRender); err != nil {
+		return nil
+	}
+	return &rawNode
+}
+
+// encodeValue is the fallback used for values that have no specific rendering logic, the value is encoded using
+// the default YAML encoder. If encoding fails (or the encoder panics), the error is recorded and nil is returned.
+func (n *NodeBuilder) encodeValue(entry *nodes.NodeEntry, value any) (valueNode *yaml.Node) {
+	defer func() {
+		if r := recover(); r != nil {
+			n.recordError(entry, fmt.Errorf("%v", r))
+			valueNode = nil
+		}
+	}()
+	var rawNode yaml.Node
+	if err := rawNode.Encode(value); err != nil {
+		n.recordError(entry, err)
+		return nil
+	}
+	rawNode.Line = entry.Line
+	return &rawNode
+}
+
+// encodeSequence renders a slice as a block style sequence. Scalars are created with the same helpers (and tags)
+// as top-level scalars, nested slices are rendered the same way, and anything else is encoded using the default
+// YAML encoder. If encoding fails (or the encoder panics), the error is returned.
+func encodeSequence(slice reflect.Value) (seq *yaml.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			seq, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	seq = utils.CreateEmptySequenceNode()
+	for i := 0; i < slice.Len(); i++ {
+		item, iErr := encodeSequenceItem(slice.Index(i))
+		if iErr != nil {
+			return nil, iErr
+		}
+		seq.Content = append(seq.Content, item)
+	}
+	return seq, nil
+}
+
+func encodeSequenceItem(v reflect.Value) (*yaml.Node, error) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return utils.CreateStringNode(v.String()), nil
+	case reflect.Bool:
+		return utils.CreateBoolNode(strconv.FormatBool(v.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return utils.CreateIntNode(strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return utils.CreateIntNode(strconv.FormatUint(v.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		val := strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+		if strings.Contains(val, ".") {
+			return utils.CreateFloatNode(val), nil
+		}
+		return utils.CreateIntNode(val), nil
+	case reflect.Slice:
+		if !v.IsNil() {
+			return encodeSequence(v)
+		}
+	}
+	var node yaml.Node
+	if err := node.Encode(v.Interface()); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// originalNumber returns a copy of the original number node from the low-level model, if the value has not been
+// changed. This keeps the exact representation used in the source, like '1.0' or '9007199254740993' (which can't
+// be represented exactly by a float64).
+func originalNumber(entry *nodes.NodeEntry, value float64) *yaml.Node {
+	vnut, ok := entry.LowValue.(low.HasValueNodeUntyped)
+	if !ok {
+		return nil
+	}
+	vn := vnut.GetValueNode()
+	if vn == nil || vn.Kind != yaml.ScalarNode || (vn.Tag != "!!int" && vn.Tag != "!!float") {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(vn.Value, 64)
+	if err != nil || parsed != value {
+		return nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: vn.Tag, Value: vn.Value}
+}
+
+// isIntegerLiteral returns true if the value is a plain decimal integer, with an optional sign.
+func isIntegerLiteral(value string) bool {
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// recordError will add a rendering error for the entry to the Errors slice, and to the Errors of the NodeBuilder
+// rendering the root object, if this NodeBuilder is rendering a nested object.
+func (n *NodeBuilder) recordError(entry *nodes.NodeEntry, err error) {
+	err = fmt.Errorf("unable to render '%s': %w", entry.Key, err)
+	n.Errors = append(n.Errors, err)
+	if n.errorSink != nil {
+		*n.errorSink = append(*n.errorSink, err)
+	}
+}
+
+// copyComments will carry over any head, line and foot comments from the original low-level key and value nodes
+// onto the newly rendered key and value nodes. New entries have no low-level nodes, so nothing is copied.
+func (n *NodeBuilder) copyComments(entry *nodes.NodeEntry, keyNode, valueNode *yaml.Node) {
+	lowKey := entry.KeyNode
+	if lowKey == nil && entry.LowValue != nil {
+		lv := reflect.ValueOf(entry.LowValue)
+		if lv.Kind() != reflect.Ptr || !lv.IsNil() {
+			if hk, ok := entry.LowValue.(low.HasKeyNode); ok {
+				lowKey = hk.GetKeyNode()
+			}
+		}
+	}
+	lowValue := lowValueNode(entry)
+	if lowKey != keyNode {
+		utils.CopyComments(lowKey, keyNode)
+	}
+	if lowValue != nil && lowValue != valueNode && lowValue.Kind == valueNode.Kind {
+		utils.CopyComments(lowValue, valueNode)
+	}
+}
+
+// lowValueNode returns the original value node for the entry, if there is one.
+func lowValueNode(entry *nodes.NodeEntry) *yaml.Node {
+	if entry.LowValue == nil {
+		return nil
+	}
+	lv := reflect.ValueOf(entry.LowValue)
+	if lv.Kind() == reflect.Ptr && lv.IsNil() {
+		return nil
+	}
+	if hv, ok := entry.LowValue.(low.HasValueNodeUntyped); ok {
+		return hv.GetValueNode()
+	}
+	return nil
+}
+
+// findComment will look up a comment for the entry, by tag first and then by field name.
+func (n *NodeBuilder) findComment(entry *nodes.NodeEntry) string {
+	if n.Comments == nil {
+		return ""
+	}
+	if c, ok := n.Comments[joinRenderPath(n.path, entry.Tag)]; ok || n.path != "" {
+		return c
+	}
+	return n.Comments[entry.Key]
+}
+
+// joinRenderPath adds a key to the path of a rendered object, keys are separated by a '.'.
+func joinRenderPath(path, key string) string {
+	if path == "" || key == "" {
+		return path + key
+	}
+	return path + "." + key
+}
+
+// findMarshaler returns the custom marshaler registered for the entry tag or field name, if there is one.
+func (n *NodeBuilder) findMarshaler(entry *nodes.NodeEntry) func(any) (*yaml.Node, error) {
+	if n.Marshalers == nil {
+		return nil
+	}
+	if m, ok := n.Marshalers[entry.Tag]; ok {
+		return m
+	}
+	return n.Marshalers[entry.Key]
+}
+
 // Renderable is an interface that can be implemented by types that provide a custom MarshalYAML method.
 type Renderable interface {
 	MarshalYAML() (interface{}, error)
 }
 
+// RenderOptions are the options of a NodeBuilder that carry over to nested objects, which are rendered by their
+// own NodeBuilder.
+type RenderOptions struct {
+	Resolve          bool
+	RenderZeroValues bool
+	SkipField        func(key string) bool
+	Marshalers       map[string]func(any) (*yaml.Node, error)
+	Comments         map[string]string
+
+	errors *[]error // the Errors of the NodeBuilder rendering the root object.
+	path   string   // the path of the nested object, from the root object.
+}
+
+// Child returns the options for rendering the nested object found under key, for objects that render their own
+// nested objects (like Paths).
+func (o RenderOptions) Child(key string) RenderOptions {
+	o.path = joinRenderPath(o.path, key)
+	return o
+}
+
+// Comment returns the comment to render above key, if there is one in Comments.
+func (o RenderOptions) Comment(key string) string {
+	return o.Comments[joinRenderPath(o.path, key)]
+}
+
+// RenderableWithOptions is an interface that can be implemented by types that render using a NodeBuilder, so the
+// options of the NodeBuilder rendering them can be applied (using NewNodeBuilderWithOptions).
+type RenderableWithOptions interface {
+	MarshalYAMLWithOptions(opts RenderOptions) (interface{}, error)
+}
+
 // RenderableInline is an interface that can be implemented by types that provide a custom MarshalYAML method.
 type RenderableInline interface {
 	MarshalYAMLInline() (interface{}, error)
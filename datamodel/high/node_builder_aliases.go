@@ -0,0 +1,139 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// aliasRestorer walks a rendered node tree alongside the source tree it was rendered from, and restores the
+// anchors and aliases used in the source.
+type aliasRestorer struct {
+	// anchors maps an anchored source node, to the rendered node that carries the anchor.
+	anchors map[*yaml.Node]*yaml.Node
+}
+
+// restoreAliases will put back the anchors and aliases from the source node into the rendered node. The first
+// rendered copy of an anchored node gets the anchor, and every other copy becomes an alias, as long as the copy
+// has not been changed. Changed copies are left expanded, so the rendered output always has the same meaning.
+func restoreAliases(rendered, source *yaml.Node) *yaml.Node {
+	r := &aliasRestorer{anchors: make(map[*yaml.Node]*yaml.Node)}
+	return r.walk(rendered, source)
+}
+
+func (r *aliasRestorer) walk(rendered, source *yaml.Node) *yaml.Node {
+	if rendered == nil || source == nil {
+		return rendered
+	}
+	if source.Kind == yaml.DocumentNode {
+		if len(source.Content) == 0 {
+			return rendered
+		}
+		source = source.Content[0]
+	}
+	if rendered.Kind == yaml.DocumentNode {
+		if len(rendered.Content) > 0 {
+			rendered.Content[0] = r.walk(rendered.Content[0], source)
+		}
+		return rendered
+	}
+
+	// the source node has been re-used as-is, so it already has its own anchors and aliases.
+	if rendered == source {
+		r.register(source)
+		return rendered
+	}
+	if rendered.Kind == yaml.AliasNode {
+		return rendered
+	}
+
+	if source.Kind == yaml.AliasNode {
+		target := source.Alias
+		if target == nil {
+			return rendered
+		}
+		if anchored, ok := r.anchors[target]; ok {
+			if nodesEqual(anchored, rendered) {
+				return &yaml.Node{Kind: yaml.AliasNode, Value: target.Anchor, Alias: anchored}
+			}
+			return r.descend(rendered, target)
+		}
+		// the anchor itself was not rendered, so this copy becomes the anchor.
+		source = target
+	}
+	if source.Anchor != "" {
+		if _, ok := r.anchors[source]; !ok {
+			rendered.Anchor = source.Anchor
+			r.anchors[source] = rendered
+		}
+	}
+	return r.descend(rendered, source)
+}
+
+// descend walks the children of a rendered mapping or sequence, matching mapping values by key and sequence
+// items by position.
+func (r *aliasRestorer) descend(rendered, source *yaml.Node) *yaml.Node {
+	switch rendered.Kind {
+	case yaml.MappingNode:
+		if source.Kind != yaml.MappingNode {
+			return rendered
+		}
+		for i := 0; i+1 < len(rendered.Content); i += 2 {
+			key := rendered.Content[i].Value
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				if source.Content[j].Value == key {
+					rendered.Content[i+1] = r.walk(rendered.Content[i+1], source.Content[j+1])
+					break
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if source.Kind != yaml.SequenceNode {
+			return rendered
+		}
+		for i := range rendered.Content {
+			if i < len(source.Content) {
+				rendered.Content[i] = r.walk(rendered.Content[i], source.Content[i])
+			}
+		}
+	}
+	return rendered
+}
+
+// register records every anchor found in a source node that is being rendered as-is.
+func (r *aliasRestorer) register(node *yaml.Node) {
+	if node == nil || node.Kind == yaml.AliasNode {
+		return
+	}
+	if node.Anchor != "" {
+		if _, ok := r.anchors[node]; !ok {
+			r.anchors[node] = node
+		}
+	}
+	for _, c := range node.Content {
+		r.register(c)
+	}
+}
+
+// nodesEqual returns true if both nodes have the same content, ignoring styles, comments and positions.
+func nodesEqual(a, b *yaml.Node) bool {
+	for a != nil && a.Kind == yaml.AliasNode {
+		a = a.Alias
+	}
+	for b != nil && b.Kind == yaml.AliasNode {
+		b = b.Alias
+	}
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind || a.ShortTag() != b.ShortTag() || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
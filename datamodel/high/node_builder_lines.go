@@ -0,0 +1,85 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// LineMap returns a map of line numbers in the original document, to line numbers in the rendered document, for
+// every key and sequence item that exists in both. It is built by Render when TrackLines is set, and is empty
+// otherwise. Rendered line numbers are the lines the node returned by Render is written to by the YAML encoder.
+//
+// Use it to translate positions reported against the original document (for example cached diagnostics), to the
+// rendered document. When more than one node starts on the same original line, the first rendered line is used.
+func (n *NodeBuilder) LineMap() map[int]int {
+	return n.lineMap
+}
+
+// buildLineMap encodes the rendered node to find the lines it is written to, then matches the rendered tree with the
+// source tree, mapping values by key and sequence items by position.
+func (n *NodeBuilder) buildLineMap(rendered, source *yaml.Node) {
+	n.lineMap = make(map[int]int)
+	b, err := yaml.Marshal(rendered)
+	if err != nil {
+		return
+	}
+	var written yaml.Node
+	if yaml.Unmarshal(b, &written) != nil || len(written.Content) == 0 {
+		return
+	}
+	n.mapLines(rendered, written.Content[0], source)
+}
+
+func (n *NodeBuilder) mapLines(rendered, written, source *yaml.Node) {
+	if rendered == nil || written == nil || source == nil {
+		return
+	}
+	if source.Kind == yaml.DocumentNode {
+		if len(source.Content) == 0 {
+			return
+		}
+		source = source.Content[0]
+	}
+	for source.Kind == yaml.AliasNode && source.Alias != nil {
+		source = source.Alias
+	}
+	if rendered.Kind == yaml.AliasNode || rendered.Kind != written.Kind {
+		return
+	}
+	switch rendered.Kind {
+	case yaml.MappingNode:
+		if source.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(rendered.Content) && i+1 < len(written.Content); i += 2 {
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				if source.Content[j].Value == rendered.Content[i].Value {
+					n.mapLine(source.Content[j].Line, written.Content[i].Line)
+					n.mapLines(rendered.Content[i+1], written.Content[i+1], source.Content[j+1])
+					break
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if source.Kind != yaml.SequenceNode {
+			return
+		}
+		for i := range rendered.Content {
+			if i < len(source.Content) && i < len(written.Content) {
+				n.mapLine(source.Content[i].Line, written.Content[i].Line)
+				n.mapLines(rendered.Content[i], written.Content[i], source.Content[i])
+			}
+		}
+	}
+}
+
+func (n *NodeBuilder) mapLine(original, rendered int) {
+	if original == 0 {
+		return
+	}
+	if existing, ok := n.lineMap[original]; !ok || rendered < existing {
+		n.lineMap[original] = rendered
+	}
+}
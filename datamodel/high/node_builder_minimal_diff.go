@@ -0,0 +1,88 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// reuseUnchanged walks a rendered node tree alongside the source tree it was rendered from, and swaps every
+// rendered node that has the same content as its source node, for the source node itself. This keeps the styles,
+// comments and formatting of everything that has not been changed. Mapping values are matched by key and sequence
+// items by position. Source nodes that use anchors or aliases are never reused, because the anchor may not be
+// rendered, their children are still checked.
+func reuseUnchanged(rendered, source *yaml.Node) *yaml.Node {
+	if rendered == nil || source == nil || rendered == source {
+		return rendered
+	}
+	if source.Kind == yaml.DocumentNode {
+		if len(source.Content) == 0 {
+			return rendered
+		}
+		source = source.Content[0]
+	}
+	if rendered.Kind == yaml.DocumentNode {
+		if len(rendered.Content) > 0 {
+			rendered.Content[0] = reuseUnchanged(rendered.Content[0], source)
+		}
+		return rendered
+	}
+	if source.Kind == yaml.AliasNode || rendered.Kind == yaml.AliasNode {
+		return rendered
+	}
+	if !hasAnchors(source) && nodesEqual(rendered, source) {
+		return source
+	}
+	switch rendered.Kind {
+	case yaml.MappingNode:
+		if source.Kind != yaml.MappingNode {
+			return rendered
+		}
+		for i := 0; i+1 < len(rendered.Content); i += 2 {
+			key := rendered.Content[i]
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				if source.Content[j].Value != key.Value {
+					continue
+				}
+				if sameComments(key, source.Content[j]) {
+					rendered.Content[i] = source.Content[j]
+				}
+				rendered.Content[i+1] = reuseUnchanged(rendered.Content[i+1], source.Content[j+1])
+				break
+			}
+		}
+	case yaml.SequenceNode:
+		if source.Kind != yaml.SequenceNode {
+			return rendered
+		}
+		for i := range rendered.Content {
+			if i < len(source.Content) {
+				rendered.Content[i] = reuseUnchanged(rendered.Content[i], source.Content[i])
+			}
+		}
+	}
+	return rendered
+}
+
+// hasAnchors returns true if the node, or any node inside it, is an anchor or an alias.
+func hasAnchors(node *yaml.Node) bool {
+	if node.Anchor != "" || node.Kind == yaml.AliasNode {
+		return true
+	}
+	for _, c := range node.Content {
+		if hasAnchors(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameComments returns true if a rendered key has no comments of its own, or the same comments as the source key.
+func sameComments(rendered, source *yaml.Node) bool {
+	if rendered.HeadComment == "" && rendered.LineComment == "" && rendered.FootComment == "" {
+		return true
+	}
+	return rendered.HeadComment == source.HeadComment && rendered.LineComment == source.LineComment &&
+		rendered.FootComment == source.FootComment
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package high
+
+import (
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SortMode controls the order that keys are rendered in by a NodeBuilder.
+type SortMode int
+
+const (
+	// SortByLine renders keys in the order they appear in the original document, this is the default. Keys that
+	// were not in the original document are rendered after those that were.
+	SortByLine SortMode = iota
+
+	// SortAlphabetical renders the keys of every map in lexicographic order, apart from the leading keys of a
+	// document ('openapi', 'swagger' and 'info') which are always rendered first. This is useful for documents
+	// that are built from scratch, which have no original order.
+	SortAlphabetical
+)
+
+// leadingKeys are rendered first, in this order, at the root of an alphabetically sorted node.
+var leadingKeys = []string{"openapi", "swagger", "info"}
+
+// sortKeys returns a copy of node with the keys of every map sorted alphabetically, the node itself is not changed,
+// so nodes shared with the source document are safe to sort. Leading keys are only honoured at the root.
+func sortKeys(node *yaml.Node, root bool) *yaml.Node {
+	if node == nil || len(node.Content) == 0 || node.Kind == yaml.AliasNode {
+		return node
+	}
+	sorted := *node
+	sorted.Content = make([]*yaml.Node, len(node.Content))
+	for i, c := range node.Content {
+		sorted.Content[i] = sortKeys(c, root && node.Kind == yaml.DocumentNode)
+	}
+	if node.Kind != yaml.MappingNode {
+		return &sorted
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(sorted.Content)/2)
+	for i := 0; i+1 < len(sorted.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{sorted.Content[i], sorted.Content[i+1]})
+	}
+	rank := func(key string) int {
+		if !root {
+			return len(leadingKeys)
+		}
+		if r := slices.Index(leadingKeys, key); r >= 0 {
+			return r
+		}
+		return len(leadingKeys)
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		ki, kj := pairs[i][0].Value, pairs[j][0].Value
+		if ri, rj := rank(ki), rank(kj); ri != rj {
+			return ri < rj
+		}
+		return ki < kj
+	})
+	for i, p := range pairs {
+		sorted.Content[i*2], sorted.Content[i*2+1] = p[0], p[1]
+	}
+	return &sorted
+}
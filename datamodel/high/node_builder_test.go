@@ -4,8 +4,12 @@
 package high
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pb33f/libopenapi/datamodel/high/nodes"
 	"github.com/pb33f/libopenapi/datamodel/low"
@@ -261,11 +265,11 @@ func TestNewNodeBuilder(t *testing.T) {
 			thoom2,
 		},
 		Thomp: thomp,
-		Thane: valueReferenceStruct{ // this is going to be ignored, needs to be a ValueReference
+		Thane: valueReferenceStruct{ // not a ValueReference, will fall back to the encoder.
 			Value: "ripples",
 		},
 		Thrug:      thrug,
-		Thump:      valueReferenceStruct{Value: "I will be ignored"},
+		Thump:      valueReferenceStruct{Value: "I will be encoded"},
 		Thunk:      valueReferenceStruct{},
 		Extensions: ext,
 	}
@@ -279,7 +283,7 @@ func TestNewNodeBuilder(t *testing.T) {
 thong: 1
 thrum: 1234567
 thang: 2.2
-thung: 3.33
+thung: 3.33333
 thyme: true
 thugg: true
 thurr: 12345
@@ -295,6 +299,8 @@ thoom:
     - ember: naughty
 thomp:
     meddy: princess
+thump: pizza
+thane: pizza
 x-pizza: time`
 
 	assert.Equal(t, desired, strings.TrimSpace(string(data)))
@@ -1108,3 +1114,368 @@ func TestNodeBuilder_GetStringLowValue(t *testing.T) {
 
 	assert.Equal(t, `thing: "thing"`, strings.TrimSpace(string(data)))
 }
+
+func TestNodeBuilder_RenderJSON(t *testing.T) {
+	b := true
+	c := int64(9007199254740993)
+
+	ext := orderedmap.New[string, *yaml.Node]()
+	ext.Set("x-pizza", utils.CreateStringNode("time"))
+	ext.Set("x-count", utils.CreateIntNode("5"))
+
+	t1 := test1{
+		Thing:      "123",
+		Thong:      1,
+		Thyme:      true,
+		Thugg:      &b,
+		Thurr:      &c,
+		Tharg:      []string{"chicken", "nuggets"},
+		Extensions: ext,
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, err := nb.RenderJSON("")
+	assert.NoError(t, err)
+
+	desired := `{"thing":"123","thong":1,"thyme":true,"thugg":true,"thurr":9007199254740993,` +
+		`"tharg":["chicken","nuggets"],"x-pizza":"time","x-count":5}`
+	assert.Equal(t, desired, string(data))
+}
+
+func TestNodeBuilder_RenderJSON_Indent(t *testing.T) {
+	t1 := test1{
+		Thing: "ding",
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, err := nb.RenderJSON("  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"thing\": \"ding\"\n}", string(data))
+}
+
+func TestNodeBuilder_RenderJSON_Scalars(t *testing.T) {
+	m := utils.CreateEmptyMapNode()
+	m.Content = append(m.Content,
+		utils.CreateStringNode("float"), utils.CreateFloatNode("0.10"),
+		utils.CreateStringNode("hex"), utils.CreateIntNode("0x10"),
+		utils.CreateStringNode("inf"), utils.CreateFloatNode(".inf"),
+		utils.CreateStringNode("null"), utils.CreateEmptyScalarNode(),
+		utils.CreateStringNode("bool"), utils.CreateBoolNode("false"),
+	)
+
+	var b bytes.Buffer
+	assert.NoError(t, writeJSONNode(&b, &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{m}}))
+	assert.Equal(t, `{"float":0.10,"hex":16,"inf":".inf","null":null,"bool":false}`, b.String())
+}
+
+func TestNodeBuilder_UnsupportedStruct_Encode(t *testing.T) {
+	type custom struct {
+		Name string `yaml:"name"`
+	}
+	type test struct {
+		Thing custom            `yaml:"thing"`
+		Thang map[string]custom `yaml:"thang"`
+	}
+
+	t1 := test{
+		Thing: custom{Name: "pizza"},
+		Thang: map[string]custom{"beer": {Name: "lager"}},
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, _ := yaml.Marshal(nb.Render())
+
+	desired := `thing:
+    name: pizza
+thang:
+    beer:
+        name: lager`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(data)))
+	assert.Empty(t, nb.Errors)
+}
+
+func TestNodeBuilder_UnsupportedStruct_EncodeError(t *testing.T) {
+	type broken struct {
+		Fn func() `yaml:"fn"`
+	}
+	type test struct {
+		Thing string `yaml:"thing"`
+		Thang broken `yaml:"thang"`
+	}
+
+	t1 := test{
+		Thing: "pizza",
+		Thang: broken{Fn: func() {}},
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, _ := yaml.Marshal(nb.Render())
+
+	assert.Equal(t, "thing: pizza", strings.TrimSpace(string(data)))
+	assert.Len(t, nb.Errors, 1)
+	assert.Contains(t, nb.Errors[0].Error(), "unable to render 'Thang'")
+}
+
+type nestedBroken struct {
+	Name string            `yaml:"name"`
+	Fn   map[string]func() `yaml:"fn"`
+}
+
+func (nb *nestedBroken) MarshalYAML() (interface{}, error) {
+	return nb.MarshalYAMLWithOptions(RenderOptions{})
+}
+
+func (nb *nestedBroken) MarshalYAMLWithOptions(opts RenderOptions) (interface{}, error) {
+	return NewNodeBuilderWithOptions(nb, nil, opts).Render(), nil
+}
+
+func TestNodeBuilder_NestedEncodeError(t *testing.T) {
+	type test struct {
+		Thing string          `yaml:"thing"`
+		Thang *nestedBroken   `yaml:"thang"`
+		Thong []*nestedBroken `yaml:"thong"`
+	}
+
+	t1 := test{
+		Thing: "pizza",
+		Thang: &nestedBroken{Name: "cake", Fn: map[string]func(){"pizza": func() {}}},
+		Thong: []*nestedBroken{{Name: "beer", Fn: map[string]func(){"pizza": func() {}}}},
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, _ := yaml.Marshal(nb.Render())
+
+	desired := `thing: pizza
+thang:
+    name: cake
+thong:
+    - name: beer`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(data)))
+
+	// errors from nested objects are recorded on the NodeBuilder rendering the root object.
+	assert.Len(t, nb.Errors, 2)
+	for _, err := range nb.Errors {
+		assert.Contains(t, err.Error(), "unable to render 'Fn'")
+	}
+}
+
+func TestNewNodeBuilder_FloatPrecision(t *testing.T) {
+	t1 := test1{
+		Thang: 0.01,
+		Thung: 123.456789012345,
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, "thang: 0.01\nthung: 123.456789012345", strings.TrimSpace(string(data)))
+}
+
+func TestNewNodeBuilder_LargeIntegerString(t *testing.T) {
+	type big struct {
+		Max string `yaml:"max,omitempty"`
+	}
+	var source yaml.Node
+	_ = yaml.Unmarshal([]byte("max: 92233720368547758070"), &source)
+	lowBig := struct {
+		Max low.NodeReference[string]
+	}{
+		Max: low.NodeReference[string]{
+			Value:     "92233720368547758070",
+			ValueNode: source.Content[0].Content[1],
+		},
+	}
+
+	nb := NewNodeBuilder(&big{Max: "92233720368547758070"}, &lowBig)
+	data, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, "max: 92233720368547758070", strings.TrimSpace(string(data)))
+
+	// without the original node, the string is quoted.
+	nb = NewNodeBuilder(&big{Max: "92233720368547758070"}, nil)
+	data, _ = yaml.Marshal(nb.Render())
+	assert.Equal(t, `max: "92233720368547758070"`, strings.TrimSpace(string(data)))
+}
+
+func TestRestoreAliases(t *testing.T) {
+	source := `tags:
+  - &pet
+    name: pet
+  - *pet
+  - *pet
+owner: *pet`
+
+	var sourceNode, rendered yaml.Node
+	_ = yaml.Unmarshal([]byte(source), &sourceNode)
+
+	// render an expanded copy, with the last item changed.
+	_ = yaml.Unmarshal([]byte(`tags:
+  - name: pet
+  - name: pet
+  - name: cat
+owner:
+  name: pet`), &rendered)
+
+	out, _ := yaml.Marshal(restoreAliases(&rendered, &sourceNode))
+	assert.Equal(t, `tags:
+    - &pet
+      name: pet
+    - *pet
+    - name: cat
+owner: *pet`, strings.TrimSpace(string(out)))
+}
+
+type rateLimitConfig struct {
+	Requests int
+	Window   string
+}
+
+func (r *rateLimitConfig) MarshalYAML() (interface{}, error) {
+	return map[string]any{"limit": fmt.Sprintf("%d/%s", r.Requests, r.Window)}, nil
+}
+
+func TestNewNodeBuilder_MapOfStructs(t *testing.T) {
+	type test struct {
+		Limits     *orderedmap.Map[string, rateLimitConfig] `yaml:"x-ratelimits,omitempty"`
+		Default    rateLimitConfig                          `yaml:"default,omitempty"`
+		Value      low.ValueReference[string]               `yaml:"value,omitempty"`
+		Plain      *orderedmap.Map[string, plainConfig]     `yaml:"plain,omitempty"`
+		Referenced *low.ValueReference[string]              `yaml:"referenced,omitempty"`
+	}
+
+	limits := orderedmap.New[string, rateLimitConfig]()
+	limits.Set("pets", rateLimitConfig{Requests: 100, Window: "1m"})
+	limits.Set("owners", rateLimitConfig{Requests: 5, Window: "1s"})
+	plain := orderedmap.New[string, plainConfig]()
+	plain.Set("pizza", plainConfig{Name: "margherita"})
+
+	t1 := test{
+		Limits:     limits,
+		Default:    rateLimitConfig{Requests: 1, Window: "1h"},
+		Value:      low.ValueReference[string]{Value: "beer", ValueNode: utils.CreateStringNode("beer")},
+		Plain:      plain,
+		Referenced: &low.ValueReference[string]{Value: "cake", ValueNode: utils.CreateStringNode("cake")},
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	data, _ := yaml.Marshal(nb.Render())
+
+	desired := `x-ratelimits:
+    pets:
+        limit: 100/1m
+    owners:
+        limit: 5/1s
+default:
+    limit: 1/1h
+value: beer
+plain:
+    pizza:
+        name: margherita
+referenced: cake`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(data)))
+	assert.Empty(t, nb.Errors)
+}
+
+type plainConfig struct {
+	Name string `yaml:"name"`
+}
+
+func TestNewNodeBuilder_Marshalers(t *testing.T) {
+	ext := orderedmap.New[string, *yaml.Node]()
+	ext.Set("x-created", utils.CreateIntNode("1700000000"))
+	t1 := test1{
+		Thing:      "pizza",
+		Thong:      1,
+		Thang:      1.5,
+		Thyme:      true,
+		Extensions: ext,
+	}
+
+	nb := NewNodeBuilder(&t1, nil)
+	nb.Marshalers = map[string]func(any) (*yaml.Node, error){
+		"x-created": func(v any) (*yaml.Node, error) {
+			var seconds int64
+			if err := v.(*yaml.Node).Decode(&seconds); err != nil {
+				return nil, err
+			}
+			return utils.CreateStringNode(time.Unix(seconds, 0).UTC().Format(time.RFC3339)), nil
+		},
+		"Thing": func(v any) (*yaml.Node, error) {
+			return utils.CreateStringNode(strings.ToUpper(v.(string))), nil
+		},
+		"thong": func(v any) (*yaml.Node, error) {
+			return nil, errors.New("no thongs")
+		},
+		"thang": func(v any) (*yaml.Node, error) {
+			return nil, nil
+		},
+	}
+	data, _ := yaml.Marshal(nb.Render())
+
+	desired := `thing: PIZZA
+thyme: true
+x-created: "2023-11-14T22:13:20Z"`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(data)))
+	assert.Len(t, nb.Errors, 1)
+	assert.Equal(t, "unable to render 'Thong': no thongs", nb.Errors[0].Error())
+}
+
+type sequences struct {
+	Strings []string   `yaml:"strings,omitempty"`
+	Ints    []int      `yaml:"ints,omitempty"`
+	Bools   []bool     `yaml:"bools,omitempty"`
+	Floats  []float64  `yaml:"floats,omitempty"`
+	Mixed   []any      `yaml:"mixed,omitempty"`
+	Nested  [][]string `yaml:"nested,omitempty"`
+	Flow    []string   `yaml:"flow,omitempty,flow"`
+}
+
+func TestNewNodeBuilder_SliceBlockStyle(t *testing.T) {
+	s := sequences{
+		Strings: []string{"a", "123", "true"},
+		Ints:    []int{1, 2},
+		Bools:   []bool{true, false},
+		Floats:  []float64{1.5, 2},
+		Mixed:   []any{"pizza", 3, nil},
+		Nested:  [][]string{{"x", "y"}, {"z"}},
+		Flow:    []string{"c", "d"},
+	}
+
+	desired := `strings:
+    - a
+    - "123"
+    - "true"
+ints:
+    - 1
+    - 2
+bools:
+    - true
+    - false
+floats:
+    - 1.5
+    - 2
+mixed:
+    - pizza
+    - 3
+    - null
+nested:
+    - - x
+      - y
+    - - z
+flow: [c, d]
+`
+	for i := 0; i < 10; i++ {
+		node := NewNodeBuilder(&s, nil).Render()
+		data, _ := yaml.Marshal(node)
+		assert.Equal(t, desired, string(data))
+	}
+
+	node := NewNodeBuilder(&s, nil).Render()
+	assert.Equal(t, "!!str", node.Content[1].Content[1].Tag)
+	assert.Equal(t, "!!int", node.Content[3].Content[0].Tag)
+	assert.Equal(t, "!!bool", node.Content[5].Content[0].Tag)
+	assert.Equal(t, "!!float", node.Content[7].Content[0].Tag)
+	assert.Equal(t, yaml.FlowStyle, node.Content[13].Style)
+}
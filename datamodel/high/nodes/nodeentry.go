@@ -10,7 +10,8 @@ type NodeEntry struct {
 	StringValue string
 	Line        int
 	KeyStyle    yaml.Style
-	// ValueStyle  yaml.Style
-	RenderZero bool
-	LowValue   any
+	ValueStyle  yaml.Style // the style of a rendered sequence, set to yaml.FlowStyle by a 'flow' tag option.
+	RenderZero  bool
+	LowValue    any
+	KeyNode     *yaml.Node // the original key node (if known), used to carry over comments.
 }
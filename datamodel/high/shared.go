@@ -14,6 +14,10 @@
 package high
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
@@ -71,3 +75,79 @@ func UnpackExtensions[T any, R low.HasExtensions[T]](low GoesLow[R]) (*orderedma
 	}
 	return m, nil
 }
+
+// GetExtensionAs finds the extension named by key, and unmarshals it into T. The extension is converted to JSON
+// and decoded with encoding/json, so json struct tags and json.Unmarshaler implementations are honored.
+//
+// obj can be a high-level model (using its Extensions map, or the low-level model if the map is not set), a
+// low-level model that implements low.HasExtensionsUntyped, a high-level extension map
+// (*orderedmap.Map[string, *yaml.Node]), a low-level extension map or a yaml mapping node. The bool result is false if the extension does not exist, the error is set if it exists but can't be
+// unmarshalled into T.
+//
+//	limit, found, err := GetExtensionAs[RateLimit](operation, "x-ratelimit")
+func GetExtensionAs[T any](obj any, key string) (T, bool, error) {
+	var result T
+	node := findExtension(obj, key)
+	if node == nil {
+		return result, false, nil
+	}
+	var raw any
+	if err := node.Decode(&raw); err != nil {
+		return result, true, fmt.Errorf("unable to decode extension '%s': %w", key, err)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return result, true, fmt.Errorf("unable to decode extension '%s': %w", key, err)
+	}
+	if err = json.Unmarshal(b, &result); err != nil {
+		return result, true, fmt.Errorf("unable to decode extension '%s': %w", key, err)
+	}
+	return result, true, nil
+}
+
+// findExtension returns the value node of the named extension, or nil if it can't be found.
+func findExtension(obj any, key string) *yaml.Node {
+	// high-level models hold their extensions (including any added by hand) in an Extensions map, the low-level
+	// model is only used if the map has not been set.
+	if field, err := extensionsField(obj); err == nil && !field.IsNil() {
+		v, _ := field.Interface().(*orderedmap.Map[string, *yaml.Node]).Get(key)
+		return v
+	}
+	switch o := obj.(type) {
+	case nil:
+		return nil
+	case *orderedmap.Map[string, *yaml.Node]:
+		v, _ := o.Get(key)
+		return v
+	case *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]]:
+		for k, v := range o.FromOldest() {
+			if k.Value == key {
+				if v.ValueNode != nil {
+					return v.ValueNode
+				}
+				return v.Value
+			}
+		}
+		return nil
+	case *yaml.Node:
+		if o.Kind == yaml.DocumentNode && len(o.Content) > 0 {
+			o = o.Content[0]
+		}
+		for i := 0; i+1 < len(o.Content); i += 2 {
+			if o.Content[i].Value == key {
+				return o.Content[i+1]
+			}
+		}
+		return nil
+	case low.HasExtensionsUntyped:
+		return findExtension(o.GetExtensions(), key)
+	case GoesLowUntyped:
+		// models created by hand have no low-level model.
+		l := o.GoLowUntyped()
+		if v := reflect.ValueOf(l); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return nil
+		}
+		return findExtension(l, key)
+	}
+	return nil
+}
@@ -135,3 +135,56 @@ power: hello`
 	assert.Error(t, er)
 	assert.Empty(t, res)
 }
+
+type rateLimit struct {
+	Requests int    `json:"requests"`
+	Window   string `json:"window"`
+}
+
+func TestGetExtensionAs(t *testing.T) {
+	var root yaml.Node
+	err := yaml.Unmarshal([]byte(`x-ratelimit:
+  requests: 100
+  window: 1m
+x-broken: nope`), &root)
+	require.NoError(t, err)
+
+	// low level model
+	c := &child{Extensions: low.ExtractExtensions(root.Content[0])}
+	limit, found, err := GetExtensionAs[rateLimit](c, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, rateLimit{Requests: 100, Window: "1m"}, limit)
+
+	// high level extension map
+	limit, found, err = GetExtensionAs[rateLimit](ExtractExtensions(c.Extensions), "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 100, limit.Requests)
+
+	// yaml node
+	limit, found, err = GetExtensionAs[rateLimit](&root, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "1m", limit.Window)
+
+	// scalar values
+	s, found, err := GetExtensionAs[string](c, "x-broken")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "nope", s)
+
+	// not found
+	_, found, err = GetExtensionAs[rateLimit](c, "x-missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = GetExtensionAs[rateLimit](nil, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// wrong type
+	_, found, err = GetExtensionAs[rateLimit](c, "x-broken")
+	assert.True(t, found)
+	assert.ErrorContains(t, err, "unable to decode extension 'x-broken'")
+}
@@ -59,75 +59,16 @@ func (c *Callback) RenderInline() ([]byte, error) {
 
 // MarshalYAML will create a ready to render YAML representation of the Paths object.
 func (c *Callback) MarshalYAML() (interface{}, error) {
-	// map keys correctly.
-	m := utils.CreateEmptyMapNode()
-	type pathItem struct {
-		pi       *PathItem
-		path     string
-		line     int
-		style    yaml.Style
-		rendered *yaml.Node
-	}
-	var mapped []*pathItem
-
-	for k, pi := range c.Expression.FromOldest() {
-		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
-		if c.low != nil {
-			lpi := c.low.FindExpression(k)
-			if lpi != nil {
-				ln = lpi.ValueNode.Line
-			}
-
-			for lk := range c.low.Expression.KeysFromOldest() {
-				if lk.Value == k {
-					style = lk.KeyNode.Style
-					break
-				}
-			}
-		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
-	}
-
-	nb := high.NewNodeBuilder(c, c.low)
-	extNode := nb.Render()
-	if extNode != nil && extNode.Content != nil {
-		var label string
-		for u := range extNode.Content {
-			if u%2 == 0 {
-				label = extNode.Content[u].Value
-				continue
-			}
-			mapped = append(mapped, &pathItem{
-				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
-			})
-		}
-	}
-
-	sort.Slice(mapped, func(i, j int) bool {
-		return mapped[i].line < mapped[j].line
-	})
-	for _, mp := range mapped {
-		if mp.pi != nil {
-			rendered, _ := mp.pi.MarshalYAML()
-
-			kn := utils.CreateStringNode(mp.path)
-			kn.Style = mp.style
-
-			m.Content = append(m.Content, kn)
-			m.Content = append(m.Content, rendered.(*yaml.Node))
-		}
-		if mp.rendered != nil {
-			m.Content = append(m.Content, utils.CreateStringNode(mp.path))
-			m.Content = append(m.Content, mp.rendered)
-		}
-	}
-
-	return m, nil
+	return c.MarshalYAMLWithOptions(high.RenderOptions{})
 }
 
 func (c *Callback) MarshalYAMLInline() (interface{}, error) {
+	return c.MarshalYAMLWithOptions(high.RenderOptions{Resolve: true})
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Callback object, using the options of
+// the NodeBuilder rendering it.
+func (c *Callback) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
 	// map keys correctly.
 	m := utils.CreateEmptyMapNode()
 	type pathItem struct {
@@ -135,13 +76,15 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 		path     string
 		line     int
 		style    yaml.Style
+		keyNode  *yaml.Node
 		rendered *yaml.Node
 	}
 	var mapped []*pathItem
 
 	for k, pi := range c.Expression.FromOldest() {
-		ln := 9999 // default to a high value to weight new content to the bottom.
+		ln := 9999 + len(mapped) // default to a high value to weight new content to the bottom, in the order it was added.
 		var style yaml.Style
+		var keyNode *yaml.Node
 		if c.low != nil {
 			lpi := c.low.FindExpression(k)
 			if lpi != nil {
@@ -151,15 +94,15 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 			for lk := range c.low.Expression.KeysFromOldest() {
 				if lk.Value == k {
 					style = lk.KeyNode.Style
+					keyNode = lk.KeyNode
 					break
 				}
 			}
 		}
-		mapped = append(mapped, &pathItem{pi, k, ln, style, nil})
+		mapped = append(mapped, &pathItem{pi, k, ln, style, keyNode, nil})
 	}
 
-	nb := high.NewNodeBuilder(c, c.low)
-	nb.Resolve = true
+	nb := high.NewNodeBuilderWithOptions(c, c.low, opts)
 	extNode := nb.Render()
 	if extNode != nil && extNode.Content != nil {
 		var label string
@@ -170,26 +113,32 @@ func (c *Callback) MarshalYAMLInline() (interface{}, error) {
 			}
 			mapped = append(mapped, &pathItem{
 				nil, label,
-				extNode.Content[u].Line, 0, extNode.Content[u],
+				extNode.Content[u].Line, 0, extNode.Content[u-1], extNode.Content[u],
 			})
 		}
 	}
 
-	sort.Slice(mapped, func(i, j int) bool {
+	sort.SliceStable(mapped, func(i, j int) bool {
 		return mapped[i].line < mapped[j].line
 	})
 	for _, mp := range mapped {
 		if mp.pi != nil {
-			rendered, _ := mp.pi.MarshalYAMLInline()
+			rendered, _ := mp.pi.MarshalYAMLWithOptions(opts.Child(mp.path))
 
 			kn := utils.CreateStringNode(mp.path)
 			kn.Style = mp.style
+			utils.CopyComments(mp.keyNode, kn)
+			if c := opts.Comment(mp.path); c != "" {
+				kn.HeadComment = c
+			}
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
 		}
 		if mp.rendered != nil {
-			m.Content = append(m.Content, utils.CreateStringNode(mp.path))
+			kn := utils.CreateStringNode(mp.path)
+			utils.CopyComments(mp.keyNode, kn)
+			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, mp.rendered)
 		}
 	}
@@ -164,8 +164,30 @@ func (c *Components) Render() ([]byte, error) {
 	return yaml.Marshal(c)
 }
 
+// RenderInline will return a YAML representation of the Components object as a byte slice, with all references
+// rendered inline.
+func (c *Components) RenderInline() ([]byte, error) {
+	d, _ := c.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Response object.
 func (c *Components) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(c, c.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLInline will create a ready to render YAML representation of the Components object, with all references
+// rendered inline.
+func (c *Components) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(c, c.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Components object, using the
+// options of the NodeBuilder rendering it.
+func (c *Components) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(c, c.low, opts)
+	return nb.Render(), nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/json"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -157,6 +158,22 @@ func (d *Document) GoLowUntyped() any {
 	return d.low
 }
 
+// EmptyPaths will return the keys of all paths that do not declare any operations. A path item that only
+// contains shared fields (like parameters, a summary or a description) or nothing at all, is usually incomplete.
+// Paths are returned in the order they are defined.
+func (d *Document) EmptyPaths() []string {
+	if d.Paths == nil || d.Paths.PathItems == nil {
+		return nil
+	}
+	var empty []string
+	for path, item := range d.Paths.PathItems.FromOldest() {
+		if item == nil || item.GetOperations().Len() == 0 {
+			empty = append(empty, path)
+		}
+	}
+	return empty
+}
+
 // Render will return a YAML representation of the Document object as a byte slice.
 func (d *Document) Render() ([]byte, error) {
 	return yaml.Marshal(d)
@@ -191,11 +208,22 @@ func (d *Document) RenderInline() ([]byte, error) {
 // MarshalYAML will create a ready to render YAML representation of the Document object.
 func (d *Document) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(d, d.low)
-	return nb.Render(), nil
+	return d.renderRoot(nb), nil
 }
 
 func (d *Document) MarshalYAMLInline() (interface{}, error) {
 	nb := high.NewNodeBuilder(d, d.low)
 	nb.Resolve = true
-	return nb.Render(), nil
+	return d.renderRoot(nb), nil
+}
+
+// renderRoot renders the document and carries over any comments from the root of the original specification.
+func (d *Document) renderRoot(nb *high.NodeBuilder) *yaml.Node {
+	rendered := nb.Render()
+	if d.low != nil && d.low.Index != nil && d.low.Index.GetConfig() != nil {
+		if info := d.low.Index.GetConfig().SpecInfo; info != nil {
+			utils.CopyComments(info.RootNode, rendered)
+		}
+	}
+	return rendered
 }
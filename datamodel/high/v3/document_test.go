@@ -4,6 +4,7 @@
 package v3
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -18,12 +19,15 @@ import (
 	"time"
 
 	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
 	lowv2 "github.com/pb33f/libopenapi/datamodel/low/v2"
 	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
 
@@ -895,9 +899,811 @@ func TestDocument_RenderJSONError(t *testing.T) {
 	}
 	h := NewDocument(lowDoc)
 
-	// render the document to YAML and it should be identical.
+	// floats must render without being mangled, so the JSON conversion succeeds.
 	r, e := h.RenderJSON(" ")
-	assert.Nil(t, r)
-	assert.Error(t, e)
-	assert.Equal(t, "yaml: cannot decode !!float `-999.99` as a !!int", e.Error())
+	assert.NoError(t, e)
+	assert.Contains(t, string(r), `"minimum": -999.99`)
+	assert.Contains(t, string(r), `"multipleOf": 0.01`)
+}
+
+func TestDocument_RenderBlockScalars(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+  description: |
+    # Pizza API
+
+    Serves **pizza**, in many shapes
+    and sizes.
+
+    - thin crust
+    - deep dish
+  summary: >
+    A folded summary
+    on two lines.
+paths:
+  /pizza:
+    get:
+      description: cake
+`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	rendered := h.RenderWithIndention(2)
+	assert.Contains(t, string(rendered), `  description: |
+    # Pizza API
+
+    Serves **pizza**, in many shapes
+    and sizes.
+
+    - thin crust
+    - deep dish
+`)
+
+	// folded text is re-flowed by the encoder, but keeps its style and value.
+	assert.Contains(t, string(rendered), "  summary: >\n")
+	var reloaded struct {
+		Info struct {
+			Summary string `yaml:"summary"`
+		} `yaml:"info"`
+	}
+	_ = yaml.Unmarshal(rendered, &reloaded)
+	assert.Equal(t, "A folded summary on two lines.\n", reloaded.Info.Summary)
+
+	// new multi-line strings, and quoted strings that become multi-line, are rendered as literal blocks.
+	h.Info.Title = "pizza\npie"
+	h.Paths.PathItems.GetOrZero("/pizza").Get.Description = "cake\nand more cake"
+	rendered = h.RenderWithIndention(2)
+	assert.Contains(t, string(rendered), "  title: |-\n    pizza\n    pie\n")
+	assert.Contains(t, string(rendered), "      description: |-\n        cake\n        and more cake\n")
+}
+
+func TestDocument_RenderBlockScalars_QuotedSource(t *testing.T) {
+	spec := `{"openapi": "3.1.0", "info": {"title": "pizza", "description": "line one\nline two"}}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// an unchanged quoted value keeps its original style.
+	rendered, _ := h.Render()
+	assert.Contains(t, string(rendered), `description: "line one\nline two"`)
+
+	h.Info.Description = "line one\nline three"
+	rendered, _ = h.Render()
+	assert.Contains(t, string(rendered), "description: |-\n        line one\n        line three")
+}
+
+func TestDocument_RenderWithComments(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+paths:
+  /pizza:
+    get:
+      description: cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.Comments = map[string]string{"paths": "AUTO-GENERATED"}
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+# AUTO-GENERATED
+paths:
+    /pizza:
+        get:
+            description: cake`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderWithComments_Paths(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+paths:
+  /pizza:
+    get:
+      description: cake
+      responses:
+        "200":
+          description: ok`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.Comments = map[string]string{
+		"info.title":       "the title",
+		"paths./pizza":     "the pizza path",
+		"paths./pizza.get": "get a pizza",
+		"paths./pizza.get.responses.200.description": "all good",
+		"description": "not the root",
+	}
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    # the title
+    title: pizza
+paths:
+    # the pizza path
+    /pizza:
+        # get a pizza
+        get:
+            description: cake
+            responses:
+                "200":
+                    # all good
+                    description: ok`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderPreservesComments(t *testing.T) {
+	spec := `# the pizza API
+openapi: 3.1.0 # version
+info: # info line
+  # the title
+  title: pizza # yum
+  description: cake
+  # foot of info
+paths:
+  # the pizza path
+  /pizza:
+    get:
+      # the description
+      description: cake # desc
+      responses:
+        # ok!
+        "200":
+          description: ok # fine
+components:
+  schemas:
+    # a pet
+    Pet:
+      type: object # obj
+      properties:
+        # pet name
+        name:
+          type: string
+x-thing: thang # ext
+`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	assert.Equal(t, spec, string(h.RenderWithIndention(2)))
+
+	// new content has no comments, and renders as normal.
+	h.Info.Description = "burgers"
+	h.Info.Version = "1.0"
+	rendered := string(h.RenderWithIndention(2))
+	assert.Contains(t, rendered, "  title: pizza # yum\n  description: burgers\n")
+	assert.Contains(t, rendered, "\n  version: \"1.0\"\n")
+}
+
+func TestDocument_EmptyPaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pizza:
+    parameters:
+      - name: slice
+        in: query
+  /burgers:
+    get:
+      description: cake
+  /nothing: {}`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	assert.Equal(t, []string{"/pizza", "/nothing"}, h.EmptyPaths())
+	assert.Nil(t, (&Document{}).EmptyPaths())
+}
+
+func TestDocument_RenderAlwaysEmitKeys(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+paths:
+  /pizza:
+    get:
+      description: cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.AlwaysEmitKeys = []string{"components"}
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+paths:
+    /pizza:
+        get:
+            description: cake
+components: {}`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// without the option, empty components are omitted.
+	nb = high.NewNodeBuilder(h, h.GoLow())
+	rendered, _ = yaml.Marshal(nb.Render())
+	assert.NotContains(t, string(rendered), "components")
+}
+
+func TestDocument_RenderAlwaysEmitKeys_Slice(t *testing.T) {
+	nb := high.NewNodeBuilder(&Document{}, nil)
+	nb.AlwaysEmitKeys = []string{"components", "tags"}
+	rendered, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, "components: {}\ntags: []", strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderSkipFields(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+  x-internal: secret
+servers:
+  - url: https://internal.pb33f.io
+x-public: yes
+x-internal: secret
+paths:
+  /pizza:
+    get:
+      description: cake
+      x-internal: secret
+      responses:
+        "200":
+          description: pizza
+          content:
+            application/json:
+              example:
+                secret: true`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	skip := map[string]bool{"servers": true, "x-internal": true, "example": true}
+	nb := high.NewNodeBuilderWithFilter(h, h.GoLow(), func(key string) bool {
+		return skip[key]
+	})
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+x-public: yes
+paths:
+    /pizza:
+        get:
+            description: cake
+            responses:
+                "200":
+                    description: pizza
+                    content:
+                        application/json: {}`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// nested objects are filtered when references are resolved too.
+	nb = high.NewNodeBuilderWithFilter(h, h.GoLow(), func(key string) bool {
+		return skip[key]
+	})
+	nb.Resolve = true
+	rendered, _ = yaml.Marshal(nb.Render())
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// the model is untouched.
+	assert.Len(t, h.Servers, 1)
+	assert.Equal(t, 2, h.Extensions.Len())
+}
+
+func TestDocument_RenderMarshalers_Nested(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+x-created: 1700000000
+paths:
+  /pizza:
+    get:
+      description: cake
+      x-created: 1700000000
+      x-broken: true`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.Marshalers = map[string]func(any) (*yaml.Node, error){
+		"x-created": func(v any) (*yaml.Node, error) {
+			var seconds int64
+			if err := v.(*yaml.Node).Decode(&seconds); err != nil {
+				return nil, err
+			}
+			return utils.CreateStringNode(time.Unix(seconds, 0).UTC().Format(time.RFC3339)), nil
+		},
+		"x-broken": func(v any) (*yaml.Node, error) {
+			return nil, errors.New("broken")
+		},
+	}
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+x-created: "2023-11-14T22:13:20Z"
+paths:
+    /pizza:
+        get:
+            description: cake
+            x-created: "2023-11-14T22:13:20Z"`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// errors from nested marshalers are recorded on the NodeBuilder rendering the document.
+	require.Len(t, nb.Errors, 1)
+	assert.Equal(t, "unable to render 'x-broken': broken", nb.Errors[0].Error())
+}
+
+func TestDocument_RenderZeroValues(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+security: []
+x-empty: ""
+paths:
+  /pizza:
+    get:
+      security: []
+      deprecated: false
+      description: cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// empty values are dropped by default.
+	rendered, _ := yaml.Marshal(high.NewNodeBuilder(h, h.GoLow()).Render())
+	assert.NotContains(t, string(rendered), "\nsecurity: []")
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.RenderZeroValues = true
+	h.Components = nil // new zero values are never rendered.
+	rendered, _ = yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+security: []
+x-empty: ""
+paths:
+    /pizza:
+        get:
+            security: []
+            deprecated: false
+            description: cake`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// an emptied value that was not empty in the original document is omitted.
+	h.Info.Title = ""
+	nb = high.NewNodeBuilder(h.Info, h.Info.GoLow())
+	nb.RenderZeroValues = true
+	rendered, _ = yaml.Marshal(nb.Render())
+	assert.Equal(t, "{}", strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderZeroValues_Nested(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+  description: ""
+paths:
+  /pizza:
+    get:
+      description: ""
+      tags: []
+      responses:
+        "200":
+          description: ""
+components:
+  schemas:
+    Pizza:
+      type: object
+      description: ""
+      required: []`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.RenderZeroValues = true
+	rendered, _ := yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: pizza
+    description: ""
+paths:
+    /pizza:
+        get:
+            description: ""
+            tags: []
+            responses:
+                "200":
+                    description: ""
+components:
+    schemas:
+        Pizza:
+            type: object
+            description: ""
+            required: []`
+
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// the option also carries over when references are resolved.
+	nb = high.NewNodeBuilder(h, h.GoLow())
+	nb.RenderZeroValues = true
+	nb.Resolve = true
+	rendered, _ = yaml.Marshal(nb.Render())
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// nested zero values are still dropped by default.
+	rendered, _ = yaml.Marshal(high.NewNodeBuilder(h, h.GoLow()).Render())
+	assert.NotContains(t, string(rendered), `description: ""`)
+	assert.NotContains(t, string(rendered), "tags: []")
+}
+
+func TestNodeBuilder_RenderZeroValues_Operation(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pizza:
+    get:
+      deprecated: false
+      description: ""
+      tags: []`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	op := h.Paths.PathItems.GetOrZero("/pizza").Get
+	nb := high.NewNodeBuilder(op, op.GoLow())
+	nb.RenderZeroValues = true
+	rendered, _ := yaml.Marshal(nb.Render())
+	assert.Equal(t, "deprecated: false\ndescription: \"\"\ntags: []", strings.TrimSpace(string(rendered)))
+}
+
+func TestDocument_RenderNewContentDeterministic(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+paths:
+  /pizza:
+    get:
+      description: cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// add a bunch of new content with no original line numbers.
+	for _, p := range []string{"/e", "/d", "/c", "/b", "/a"} {
+		h.Paths.PathItems.Set(p, &PathItem{Description: p})
+	}
+	h.Extensions = orderedmap.New[string, *yaml.Node]()
+	for _, x := range []string{"x-e", "x-d", "x-c", "x-b", "x-a"} {
+		h.Extensions.Set(x, utils.CreateStringNode(x))
+	}
+	h.Info.Summary = "new summary"
+	h.Info.Description = "new description"
+
+	first, err := h.Render()
+	assert.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		again, _ := h.Render()
+		assert.Equal(t, string(first), string(again))
+	}
+
+	// new paths are rendered in the order they were added.
+	rendered := string(first)
+	last := strings.Index(rendered, "/pizza:")
+	for _, p := range []string{"/e:", "/d:", "/c:", "/b:", "/a:"} {
+		idx := strings.Index(rendered, p)
+		assert.Greater(t, idx, last, p)
+		last = idx
+	}
+	last = -1
+	for _, x := range []string{"x-e:", "x-d:", "x-c:", "x-b:", "x-a:"} {
+		idx := strings.Index(rendered, x)
+		assert.Greater(t, idx, last, x)
+		last = idx
+	}
+}
+
+func TestDocument_RenderPreserveAliases(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: aliases
+paths:
+  /pizza:
+    get:
+      responses:
+        "404": &notFound
+          description: not found
+          content:
+            application/json:
+              schema:
+                type: string
+    post:
+      responses:
+        "404": *notFound
+    put:
+      responses:
+        "404": *notFound`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// aliases are expanded by default.
+	rendered, _ := yaml.Marshal(high.NewNodeBuilder(h, h.GoLow()).Render())
+	assert.NotContains(t, string(rendered), "*notFound")
+	assert.Equal(t, 3, strings.Count(string(rendered), "description: not found"))
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.PreserveAliases = true
+	rendered, _ = yaml.Marshal(nb.Render())
+
+	desired := `openapi: 3.1.0
+info:
+    title: aliases
+paths:
+    /pizza:
+        get:
+            responses:
+                "404": &notFound
+                    description: not found
+                    content:
+                        application/json:
+                            schema:
+                                type: string
+        post:
+            responses:
+                "404": *notFound
+        put:
+            responses:
+                "404": *notFound`
+	assert.Equal(t, desired, strings.TrimSpace(string(rendered)))
+
+	// a changed copy is no longer an alias.
+	h.Paths.PathItems.GetOrZero("/pizza").Post.Responses.Codes.GetOrZero("404").Description = "gone"
+	nb = high.NewNodeBuilder(h, h.GoLow())
+	nb.PreserveAliases = true
+	rendered, _ = yaml.Marshal(nb.Render())
+	assert.Equal(t, 1, strings.Count(string(rendered), "*notFound"))
+	assert.Contains(t, string(rendered), "description: gone")
+
+	// the output has the same meaning as the fully expanded render.
+	var withAliases, expanded any
+	assert.NoError(t, yaml.Unmarshal(rendered, &withAliases))
+	rendered, _ = yaml.Marshal(high.NewNodeBuilder(h, h.GoLow()).Render())
+	assert.NoError(t, yaml.Unmarshal(rendered, &expanded))
+	assert.Equal(t, expanded, withAliases)
+}
+
+func TestDocument_RenderExplicitFalse(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: pizza
+paths:
+  /pizza:
+    get:
+      deprecated: false
+      parameters:
+        - name: size
+          in: query
+          required: false
+          allowEmptyValue: false
+      description: cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+
+	// editing another field keeps the explicit false values.
+	op := h.Paths.PathItems.GetOrZero("/pizza").Get
+	op.Description = "pie"
+	rendered, _ := h.Render()
+	assert.Equal(t, strings.Replace(spec, "cake", "pie", 1)+"\n", strings.ReplaceAll(string(rendered), "    ", "  "))
+
+	// new false values are not rendered.
+	f := false
+	h.Paths.PathItems.GetOrZero("/pizza").Post = &Operation{Description: "new", Deprecated: &f}
+	rendered, _ = h.Render()
+	assert.Equal(t, 1, strings.Count(string(rendered), "deprecated: false"))
+}
+
+func TestDocument_RenderMinimalDiff(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: 'minimal' # the title
+  version: "1.0"
+tags: [{name: pizza}, {name: cake}]
+paths:
+  /pizza:
+    get:
+      # fetch a pizza
+      description: "get a pizza"
+      parameters: [{name: size, in: query}]
+      responses:
+        "200":
+          description: 'OK'`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+	h.Info.Version = "2.0"
+	h.Paths.PathItems.GetOrZero("/pizza").Get.Description = "get a large pizza"
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	nb.MinimalDiff = true
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	assert.NoError(t, enc.Encode(nb.Render()))
+
+	desired := `openapi: 3.1.0
+info:
+  title: 'minimal' # the title
+  version: "2.0"
+tags: [{name: pizza}, {name: cake}]
+paths:
+  /pizza:
+    get:
+      # fetch a pizza
+      description: "get a large pizza"
+      parameters: [{name: size, in: query}]
+      responses:
+        "200":
+          description: 'OK'`
+	assert.Equal(t, desired, strings.TrimSpace(b.String()))
+
+	// nothing changed, so nothing is rendered fresh.
+	lDoc, _ = lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	h = NewDocument(lDoc)
+	nb = high.NewNodeBuilder(h, h.GoLow())
+	nb.MinimalDiff = true
+	assert.Same(t, lDoc.GetRootNode().Content[0], nb.Render())
+}
+
+func TestDocument_RenderLineMap(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: lines
+  description: |
+    a description
+    over two lines
+paths:
+  /pizza:
+    get:
+      description: get a pizza
+  /cake:
+    get:
+      description: get a cake`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	h := NewDocument(lDoc)
+	h.Info.Description = "a short description" // still a literal block, but one line shorter.
+
+	nb := high.NewNodeBuilder(h, h.GoLow())
+	assert.Nil(t, nb.LineMap())
+	nb.TrackLines = true
+	rendered, _ := yaml.Marshal(nb.Render())
+	lines := strings.Split(string(rendered), "\n")
+
+	lineMap := nb.LineMap()
+	assert.Equal(t, 1, lineMap[1])
+	assert.Equal(t, 4, lineMap[4])
+	assert.Equal(t, 6, lineMap[7])
+
+	// every mapped line holds the same key in the rendered document.
+	for _, key := range []string{"/pizza:", "/cake:", "description: get a cake"} {
+		orig := strings.Index(spec, key)
+		origLine := strings.Count(spec[:orig], "\n") + 1
+		assert.Contains(t, lines[lineMap[origLine]-1], key)
+	}
+	_, ok := lineMap[6]
+	assert.False(t, ok)
+}
+
+func TestDocument_RenderSortAlphabetical(t *testing.T) {
+	pathItems := orderedmap.New[string, *PathItem]()
+	pathItems.Set("/zebras", &PathItem{Get: &Operation{OperationId: "getZebras", Description: "zebras"}})
+	pathItems.Set("/ants", &PathItem{Post: &Operation{OperationId: "addAnt"}})
+	properties := orderedmap.New[string, *base.SchemaProxy]()
+	properties.Set("name", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}}))
+	properties.Set("age", base.CreateSchemaProxy(&base.Schema{Type: []string{"integer"}}))
+	schemas := orderedmap.New[string, *base.SchemaProxy]()
+	schemas.Set("Pet", base.CreateSchemaProxy(&base.Schema{Type: []string{"object"}, Properties: properties}))
+
+	h := &Document{
+		Version:    "3.1.0",
+		Info:       &base.Info{Version: "1.0", Title: "generated"},
+		Paths:      &Paths{PathItems: pathItems},
+		Components: &Components{Schemas: schemas},
+		Tags:       []*base.Tag{{Name: "zebras", Description: "stripes"}},
+	}
+
+	nb := high.NewNodeBuilder(h, nil)
+	nb.SortMode = high.SortAlphabetical
+	rendered, err := yaml.Marshal(nb.Render())
+	assert.NoError(t, err)
+
+	desired := `openapi: 3.1.0
+info:
+    title: generated
+    version: "1.0"
+components:
+    schemas:
+        Pet:
+            properties:
+                age:
+                    type: integer
+                name:
+                    type: string
+            type: object
+paths:
+    /ants:
+        post:
+            operationId: addAnt
+    /zebras:
+        get:
+            description: zebras
+            operationId: getZebras
+tags:
+    - description: stripes
+      name: zebras
+`
+	assert.Equal(t, desired, string(rendered))
+
+	// the model is not changed.
+	assert.Equal(t, "/zebras", pathItems.First().Key())
 }
@@ -52,12 +52,34 @@ func (e *Encoding) Render() ([]byte, error) {
 	return yaml.Marshal(e)
 }
 
+// RenderInline will return a YAML representation of the Encoding object as a byte slice, with all references
+// rendered inline.
+func (e *Encoding) RenderInline() ([]byte, error) {
+	d, _ := e.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Encoding object.
 func (e *Encoding) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(e, e.low)
 	return nb.Render(), nil
 }
 
+// MarshalYAMLInline will create a ready to render YAML representation of the Encoding object, with all references
+// rendered inline.
+func (e *Encoding) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(e, e.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Encoding object, using the options of
+// the NodeBuilder rendering it.
+func (e *Encoding) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(e, e.low, opts)
+	return nb.Render(), nil
+}
+
 // ExtractEncoding converts hard to navigate low-level plumbing Encoding definitions, into a high-level simple map
 func ExtractEncoding(elements *orderedmap.Map[lowmodel.KeyReference[string], lowmodel.ValueReference[*lowv3.Encoding]]) *orderedmap.Map[string, *Encoding] {
 	return low.FromReferenceMapWithFunc(elements, NewEncoding)
@@ -77,8 +77,30 @@ func (h *Header) Render() ([]byte, error) {
 	return yaml.Marshal(h)
 }
 
+// RenderInline will return a YAML representation of the Header object as a byte slice, with all references
+// rendered inline.
+func (h *Header) RenderInline() ([]byte, error) {
+	d, _ := h.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Header object.
 func (h *Header) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(h, h.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLInline will create a ready to render YAML representation of the Header object, with all references
+// rendered inline.
+func (h *Header) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(h, h.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Header object, using the options of
+// the NodeBuilder rendering it.
+func (h *Header) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(h, h.low, opts)
+	return nb.Render(), nil
+}
@@ -65,8 +65,30 @@ func (l *Link) Render() ([]byte, error) {
 	return yaml.Marshal(l)
 }
 
+// RenderInline will return a YAML representation of the Link object as a byte slice, with all references
+// rendered inline.
+func (l *Link) RenderInline() ([]byte, error) {
+	d, _ := l.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Link object.
 func (l *Link) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(l, l.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLInline will create a ready to render YAML representation of the Link object, with all references
+// rendered inline.
+func (l *Link) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(l, l.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Link object, using the options of
+// the NodeBuilder rendering it.
+func (l *Link) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(l, l.low, opts)
+	return nb.Render(), nil
+}
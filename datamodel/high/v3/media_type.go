@@ -72,6 +72,13 @@ func (m *MediaType) MarshalYAMLInline() (interface{}, error) {
 	return nb.Render(), nil
 }
 
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the MediaType object, using the
+// options of the NodeBuilder rendering it.
+func (m *MediaType) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(m, m.low, opts)
+	return nb.Render(), nil
+}
+
 // ExtractContent takes in a complex and hard to navigate low-level content map, and converts it in to a much simpler
 // and easier to navigate high-level one.
 func ExtractContent(elements *orderedmap.Map[lowmodel.KeyReference[string], lowmodel.ValueReference[*low.MediaType]]) *orderedmap.Map[string, *MediaType] {
@@ -0,0 +1,188 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DuplicatePair represents two component schemas that are considered duplicates of one another. Left is the
+// schema that appears first in the components object, Right is the one that follows it.
+type DuplicatePair struct {
+	Left  string `json:"left" yaml:"left"`
+	Right string `json:"right" yaml:"right"`
+}
+
+// NullabilityDuplicates will look through all component schemas and return every pair of schemas that are identical
+// except for nullability. Nullability is expressed either using the 3.0 `nullable` keyword, or by adding `null` to
+// the type array in 3.1. Schemas that are completely identical (including nullability) are not reported.
+//
+// Each schema is rendered and then canonicalized (keys sorted, nullability removed) before being compared.
+func (d *Document) NullabilityDuplicates() []DuplicatePair {
+	if d.Components == nil || d.Components.Schemas == nil {
+		return nil
+	}
+
+	type canonicalSchema struct {
+		name     string
+		full     string
+		nullFree string
+	}
+
+	var schemas []canonicalSchema
+	for name, proxy := range d.Components.Schemas.FromOldest() {
+		if proxy == nil || proxy.IsReference() {
+			continue
+		}
+		rendered, err := proxy.MarshalYAML()
+		if err != nil || rendered == nil {
+			continue
+		}
+		node, ok := rendered.(*yaml.Node)
+		if !ok {
+			continue
+		}
+		full, err := yaml.Marshal(canonicalizeSchemaNode(node, false))
+		if err != nil {
+			continue
+		}
+		nullFree, err := yaml.Marshal(canonicalizeSchemaNode(node, true))
+		if err != nil {
+			continue
+		}
+		schemas = append(schemas, canonicalSchema{name: name, full: string(full), nullFree: string(nullFree)})
+	}
+
+	var pairs []DuplicatePair
+	for i := range schemas {
+		for j := i + 1; j < len(schemas); j++ {
+			if schemas[i].nullFree == schemas[j].nullFree && schemas[i].full != schemas[j].full {
+				pairs = append(pairs, DuplicatePair{Left: schemas[i].name, Right: schemas[j].name})
+			}
+		}
+	}
+	return pairs
+}
+
+// canonicalPosition is the position of a node within a rendered schema, which decides whether keys are keywords.
+type canonicalPosition int
+
+const (
+	dataPosition      canonicalPosition = iota // a value, such as an example or an enum, nothing is stripped.
+	schemaPosition                             // a schema (or a sequence of schemas), keys are keywords.
+	schemaMapPosition                          // a map of names to schemas, such as properties.
+)
+
+// schemaKeywords are the keywords that hold a schema, or a sequence of schemas.
+var schemaKeywords = map[string]bool{
+	"allOf": true, "anyOf": true, "oneOf": true, "not": true, "items": true, "prefixItems": true,
+	"additionalItems": true, "contains": true, "additionalProperties": true, "propertyNames": true,
+	"unevaluatedItems": true, "unevaluatedProperties": true, "if": true, "then": true, "else": true,
+	"contentSchema": true,
+}
+
+// schemaMapKeywords are the keywords that hold a map of names to schemas.
+var schemaMapKeywords = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true,
+}
+
+// canonicalizeSchemaNode creates a copy of a rendered schema node with all mapping keys sorted. If stripNullability
+// is true, then boolean `nullable` keywords are removed and `null` is removed from any type keywords, in the schema
+// and all of its sub-schemas. Property names and values (such as examples) are never stripped.
+func canonicalizeSchemaNode(node *yaml.Node, stripNullability bool) *yaml.Node {
+	return canonicalizeNode(node, stripNullability, schemaPosition)
+}
+
+// canonicalizeNode creates a sorted copy of a node found at the given position.
+func canonicalizeNode(node *yaml.Node, stripNullability bool, position canonicalPosition) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		return canonicalizeNode(node.Content[0], stripNullability, position)
+	}
+
+	c := &yaml.Node{Kind: node.Kind, Tag: node.Tag, Value: node.Value}
+	switch node.Kind {
+	case yaml.MappingNode:
+		type kv struct {
+			key   *yaml.Node
+			value *yaml.Node
+		}
+		var entries []kv
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			valuePosition := dataPosition
+			switch position {
+			case schemaMapPosition:
+				valuePosition = schemaPosition
+			case schemaPosition:
+				if stripNullability {
+					if k.Value == "nullable" && v.Kind == yaml.ScalarNode && v.Tag == "!!bool" {
+						continue
+					}
+					if k.Value == "type" && (v.Kind == yaml.ScalarNode || v.Kind == yaml.SequenceNode) {
+						v = stripNullType(v)
+						if v == nil {
+							continue
+						}
+					}
+				}
+				if schemaKeywords[k.Value] {
+					valuePosition = schemaPosition
+				} else if schemaMapKeywords[k.Value] {
+					valuePosition = schemaMapPosition
+				}
+			}
+			entries = append(entries, kv{
+				key:   &yaml.Node{Kind: k.Kind, Tag: k.Tag, Value: k.Value},
+				value: canonicalizeNode(v, stripNullability, valuePosition),
+			})
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].key.Value < entries[j].key.Value
+		})
+		for _, e := range entries {
+			c.Content = append(c.Content, e.key, e.value)
+		}
+	case yaml.SequenceNode:
+		// a sequence of schemas (such as allOf) holds schemas, anything else holds values.
+		itemPosition := dataPosition
+		if position == schemaPosition {
+			itemPosition = schemaPosition
+		}
+		for _, v := range node.Content {
+			c.Content = append(c.Content, canonicalizeNode(v, stripNullability, itemPosition))
+		}
+	case yaml.AliasNode:
+		return canonicalizeNode(node.Alias, stripNullability, position)
+	}
+	return c
+}
+
+// stripNullType removes `null` from a type node. A type array that is left with a single value is collapsed
+// into a scalar, so `[string, null]` and `string` are considered the same.
+func stripNullType(node *yaml.Node) *yaml.Node {
+	if node.Kind != yaml.SequenceNode {
+		if node.Value == "null" {
+			return nil
+		}
+		return node
+	}
+	var types []*yaml.Node
+	for _, t := range node.Content {
+		if t.Value != "null" {
+			types = append(types, t)
+		}
+	}
+	switch len(types) {
+	case 0:
+		return nil
+	case 1:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: types[0].Value}
+	}
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: types}
+}
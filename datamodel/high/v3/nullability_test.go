@@ -0,0 +1,134 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_NullabilityDuplicates(t *testing.T) {
+	spec := `openapi: 3.0.3
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+    NullablePet:
+      type: object
+      nullable: true
+      properties:
+        age:
+          type: integer
+        name:
+          type: string
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	d := buildTestDocument(t, spec)
+	pairs := d.NullabilityDuplicates()
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, DuplicatePair{Left: "Pet", Right: "NullablePet"}, pairs[0])
+}
+
+func TestDocument_NullabilityDuplicates_TypeArray(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+    NullablePet:
+      type: [object, "null"]
+      properties:
+        name:
+          type: string`
+
+	d := buildTestDocument(t, spec)
+	pairs := d.NullabilityDuplicates()
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, "Pet", pairs[0].Left)
+	assert.Equal(t, "NullablePet", pairs[0].Right)
+}
+
+func TestDocument_NullabilityDuplicates_IdenticalNotReported(t *testing.T) {
+	spec := `openapi: 3.0.3
+components:
+  schemas:
+    Pet:
+      type: object
+      nullable: true
+    OtherPet:
+      type: object
+      nullable: true`
+
+	d := buildTestDocument(t, spec)
+	assert.Empty(t, d.NullabilityDuplicates())
+}
+
+func TestDocument_NullabilityDuplicates_PropertyNamedNullable(t *testing.T) {
+	spec := `openapi: 3.0.3
+components:
+  schemas:
+    Flag:
+      type: object
+      properties:
+        name:
+          type: string
+        nullable:
+          type: boolean
+    Plain:
+      type: object
+      nullable: true
+      properties:
+        name:
+          type: string
+    Example:
+      type: object
+      example:
+        nullable: true
+        type: "null"`
+
+	d := buildTestDocument(t, spec)
+	assert.Empty(t, d.NullabilityDuplicates())
+}
+
+func TestDocument_NullabilityDuplicates_NestedSchemas(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tags:
+          type: array
+          items:
+            type: string
+    NullablePet:
+      type: object
+      properties:
+        tags:
+          type: array
+          items:
+            type: [string, "null"]`
+
+	d := buildTestDocument(t, spec)
+	pairs := d.NullabilityDuplicates()
+	assert.Equal(t, []DuplicatePair{{Left: "Pet", Right: "NullablePet"}}, pairs)
+}
+
+func TestDocument_NullabilityDuplicates_NoComponents(t *testing.T) {
+	d := &Document{}
+	assert.Nil(t, d.NullabilityDuplicates())
+}
@@ -54,3 +54,10 @@ func (o *OAuthFlow) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(o, o.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the OAuthFlow object, using the
+// options of the NodeBuilder rendering it.
+func (o *OAuthFlow) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(o, o.low, opts)
+	return nb.Render(), nil
+}
@@ -121,3 +121,10 @@ func (o *Operation) MarshalYAMLInline() (interface{}, error) {
 	nb.Resolve = true
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Operation object, using the
+// options of the NodeBuilder rendering it.
+func (o *Operation) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(o, o.low, opts)
+	return nb.Render(), nil
+}
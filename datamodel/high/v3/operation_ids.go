@@ -0,0 +1,87 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// OperationIdLocation describes where an operationId is defined.
+type OperationIdLocation struct {
+	OperationId string `json:"operationId" yaml:"operationId"`
+	Section     string `json:"section" yaml:"section"` // either 'paths' or 'webhooks'
+	Path        string `json:"path" yaml:"path"`       // the path (or webhook name) the operation belongs to
+	Method      string `json:"method" yaml:"method"`
+	Line        int    `json:"line" yaml:"line"`
+	Column      int    `json:"column" yaml:"column"`
+}
+
+// OperationIdCollision represents an operationId that is used by more than one operation.
+type OperationIdCollision struct {
+	OperationId string                 `json:"operationId" yaml:"operationId"`
+	Locations   []*OperationIdLocation `json:"locations" yaml:"locations"`
+}
+
+// OperationIds will collect every operationId defined by operations in both `paths` and `webhooks`, in the
+// order they are defined. Operations without an operationId are skipped.
+func (d *Document) OperationIds() []*OperationIdLocation {
+	var ids []*OperationIdLocation
+	if d.Paths != nil {
+		ids = append(ids, collectOperationIds("paths", d.Paths.PathItems)...)
+	}
+	ids = append(ids, collectOperationIds("webhooks", d.Webhooks)...)
+	return ids
+}
+
+// DuplicateOperationIds will return every operationId that is used more than once. OperationIds must be unique
+// across all operations described by the document, so operations in `paths` and `webhooks` are checked together.
+func (d *Document) DuplicateOperationIds() []*OperationIdCollision {
+	seen := make(map[string]*OperationIdCollision)
+	var order []*OperationIdCollision
+	for _, loc := range d.OperationIds() {
+		c, ok := seen[loc.OperationId]
+		if !ok {
+			c = &OperationIdCollision{OperationId: loc.OperationId}
+			seen[loc.OperationId] = c
+			order = append(order, c)
+		}
+		c.Locations = append(c.Locations, loc)
+	}
+	var collisions []*OperationIdCollision
+	for _, c := range order {
+		if len(c.Locations) > 1 {
+			collisions = append(collisions, c)
+		}
+	}
+	return collisions
+}
+
+func collectOperationIds(section string, items *orderedmap.Map[string, *PathItem]) []*OperationIdLocation {
+	if items == nil {
+		return nil
+	}
+	var ids []*OperationIdLocation
+	for path, item := range items.FromOldest() {
+		if item == nil {
+			continue
+		}
+		for method, op := range item.GetOperations().FromOldest() {
+			if op == nil || op.OperationId == "" {
+				continue
+			}
+			loc := &OperationIdLocation{
+				OperationId: op.OperationId,
+				Section:     section,
+				Path:        path,
+				Method:      method,
+			}
+			if low := op.GoLow(); low != nil && low.OperationId.ValueNode != nil {
+				loc.Line = low.OperationId.ValueNode.Line
+				loc.Column = low.OperationId.ValueNode.Column
+			}
+			ids = append(ids, loc)
+		}
+	}
+	return ids
+}
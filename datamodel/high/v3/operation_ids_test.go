@@ -0,0 +1,51 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_DuplicateOperationIds_PathsAndWebhooks(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: newPet
+webhooks:
+  newPet:
+    post:
+      operationId: newPet
+  oldPet:
+    post:
+      operationId: oldPet`
+
+	d := buildTestDocument(t, spec)
+	assert.Len(t, d.OperationIds(), 4)
+
+	collisions := d.DuplicateOperationIds()
+	assert.Len(t, collisions, 1)
+	assert.Equal(t, "newPet", collisions[0].OperationId)
+	assert.Len(t, collisions[0].Locations, 2)
+
+	first, second := collisions[0].Locations[0], collisions[0].Locations[1]
+	assert.Equal(t, "paths", first.Section)
+	assert.Equal(t, "/pets", first.Path)
+	assert.Equal(t, "post", first.Method)
+	assert.Equal(t, 7, first.Line)
+	assert.Equal(t, "webhooks", second.Section)
+	assert.Equal(t, "newPet", second.Path)
+	assert.Equal(t, "post", second.Method)
+	assert.Equal(t, 11, second.Line)
+}
+
+func TestDocument_DuplicateOperationIds_None(t *testing.T) {
+	d := &Document{}
+	assert.Empty(t, d.OperationIds())
+	assert.Empty(t, d.DuplicateOperationIds())
+}
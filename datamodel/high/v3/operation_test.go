@@ -8,11 +8,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel/high"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 
 	"github.com/pb33f/libopenapi/datamodel/low"
 	v3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -161,3 +164,133 @@ func TestOperation_NoSecurity(t *testing.T) {
 
 	assert.Nil(t, r.Security)
 }
+
+func TestOperation_GetExtensionAs(t *testing.T) {
+	yml := `operationId: getPets
+x-ratelimit:
+  requests: 100
+  window: 1m`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(&idxNode, &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	type rateLimit struct {
+		Requests int    `json:"requests"`
+		Window   string `json:"window"`
+	}
+	limit, found, err := high.GetExtensionAs[rateLimit](NewOperation(&n), "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 100, limit.Requests)
+	assert.Equal(t, "1m", limit.Window)
+}
+
+func TestOperation_GetExtensionAs_NoLowModel(t *testing.T) {
+	ext := orderedmap.New[string, *yaml.Node]()
+	ext.Set("x-ratelimit", utils.CreateIntNode("100"))
+	op := &Operation{Extensions: ext}
+
+	limit, found, err := high.GetExtensionAs[int](op, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 100, limit)
+
+	_, found, err = high.GetExtensionAs[int](op, "x-missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// no extensions, and no low-level model to fall back on.
+	_, found, err = high.GetExtensionAs[int](&Operation{}, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestOperation_GetExtensionAs_AddedByHand(t *testing.T) {
+	yml := `operationId: getPets
+x-ratelimit: 100`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(idxNode.Content[0], &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	op := NewOperation(&n)
+	assert.NoError(t, high.AddExtension(op, "x-ratelimit", 200))
+
+	// the high-level extension map is used, so changes made by hand are found.
+	limit, found, err := high.GetExtensionAs[int](op, "x-ratelimit")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 200, limit)
+}
+
+func TestOperation_AddExtension(t *testing.T) {
+	yml := `operationId: getPets
+x-ratelimit: 100`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(idxNode.Content[0], &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	op := NewOperation(&n)
+	assert.NoError(t, high.AddExtension(op, "x-owner", "pets-team"))
+	assert.NoError(t, high.AddExtension(op, "x-internal", true))
+	assert.NoError(t, high.AddExtension(op, "x-audit", "required"))
+
+	expected := `operationId: getPets
+x-ratelimit: 100
+x-owner: pets-team
+x-internal: true
+x-audit: required`
+
+	for i := 0; i < 10; i++ {
+		rend, _ := op.Render()
+		assert.Equal(t, expected, strings.TrimSpace(string(rend)))
+	}
+	assert.Len(t, high.OrderedExtensions(op), 4)
+}
+
+func TestOperation_RenderStandalone(t *testing.T) {
+	yml := `summary: list pets
+x-team: pets
+operationId: listPets
+tags:
+    - pets
+responses:
+    "200":
+        description: ok
+x-audit: required`
+
+	var idxNode yaml.Node
+	_ = yaml.Unmarshal([]byte(yml), &idxNode)
+	idx := index.NewSpecIndex(&idxNode)
+
+	var n v3.Operation
+	_ = low.BuildModel(idxNode.Content[0], &n)
+	_ = n.Build(context.Background(), nil, idxNode.Content[0], idx)
+
+	op := NewOperation(&n)
+	rend, err := op.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, yml, strings.TrimSpace(string(rend)))
+
+	// a hand built operation (with no low-level model) renders in field order, extensions last.
+	op = &Operation{Summary: "create pet", OperationId: "createPet", Tags: []string{"pets"}}
+	assert.NoError(t, high.AddExtension(op, "x-one", 1))
+	assert.NoError(t, high.AddExtension(op, "x-two", 2))
+	rend, err = op.Render()
+	assert.NoError(t, err)
+	assert.Equal(t, "tags:\n    - pets\nsummary: create pet\noperationId: createPet\nx-one: 1\nx-two: 2", strings.TrimSpace(string(rend)))
+}
@@ -7,12 +7,22 @@ import (
 	"fmt"
 	"github.com/pb33f/libopenapi/utils"
 	"os"
+	"testing"
 
 	"github.com/pb33f/libopenapi/datamodel"
 	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/require"
 )
 
+// buildTestDocument creates a high-level document from a specification, using the default configuration.
+func buildTestDocument(t *testing.T, spec string) *Document {
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	return NewDocument(lDoc)
+}
+
 // An example of how to create a new high-level OpenAPI 3+ document from an OpenAPI specification.
 func Example_createHighLevelOpenAPIDocument() {
 	// Load in an OpenAPI 3+ specification as a byte slice.
@@ -92,6 +92,13 @@ func (p *Parameter) MarshalYAMLInline() (interface{}, error) {
 	return nb.Render(), nil
 }
 
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Parameter object, using the
+// options of the NodeBuilder rendering it.
+func (p *Parameter) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(p, p.low, opts)
+	return nb.Render(), nil
+}
+
 // IsExploded will return true if the parameter is exploded, false otherwise.
 func (p *Parameter) IsExploded() bool {
 	if p.Explode == nil {
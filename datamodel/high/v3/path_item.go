@@ -218,3 +218,10 @@ func (p *PathItem) MarshalYAMLInline() (interface{}, error) {
 
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the PathItem object, using the options of
+// the NodeBuilder rendering it.
+func (p *PathItem) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(p, p.low, opts)
+	return nb.Render(), nil
+}
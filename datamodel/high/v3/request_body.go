@@ -64,3 +64,10 @@ func (r *RequestBody) MarshalYAMLInline() (interface{}, error) {
 	nb.Resolve = true
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the RequestBody object, using the
+// options of the NodeBuilder rendering it.
+func (r *RequestBody) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(r, r.low, opts)
+	return nb.Render(), nil
+}
@@ -74,3 +74,10 @@ func (r *Response) MarshalYAMLInline() (interface{}, error) {
 	nb.Resolve = true
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Response object, using the options of
+// the NodeBuilder rendering it.
+func (r *Response) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(r, r.low, opts)
+	return nb.Render(), nil
+}
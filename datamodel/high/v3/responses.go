@@ -90,99 +90,46 @@ func (r *Responses) RenderInline() ([]byte, error) {
 
 // MarshalYAML will create a ready to render YAML representation of the Responses object.
 func (r *Responses) MarshalYAML() (interface{}, error) {
-	// map keys correctly.
-	m := utils.CreateEmptyMapNode()
-	type responseItem struct {
-		resp  *Response
-		code  string
-		line  int
-		ext   *yaml.Node
-		style yaml.Style
-	}
-	var mapped []*responseItem
-
-	for code, resp := range r.Codes.FromOldest() {
-		ln := 9999 // default to a high value to weight new content to the bottom.
-		var style yaml.Style
-		if r.low != nil {
-			for lk := range r.low.Codes.KeysFromOldest() {
-				if lk.Value == code {
-					ln = lk.KeyNode.Line
-					style = lk.KeyNode.Style
-				}
-			}
-		}
-		mapped = append(mapped, &responseItem{resp, code, ln, nil, style})
-	}
-
-	// extract extensions
-	nb := high.NewNodeBuilder(r, r.low)
-	extNode := nb.Render()
-	if extNode != nil && extNode.Content != nil {
-		var label string
-		for u := range extNode.Content {
-			if u%2 == 0 {
-				label = extNode.Content[u].Value
-				continue
-			}
-			mapped = append(mapped, &responseItem{
-				nil, label,
-				extNode.Content[u].Line, extNode.Content[u], 0,
-			})
-		}
-	}
-
-	sort.Slice(mapped, func(i, j int) bool {
-		return mapped[i].line < mapped[j].line
-	})
-	for _, mp := range mapped {
-		if mp.resp != nil {
-			rendered, _ := mp.resp.MarshalYAML()
-
-			kn := utils.CreateStringNode(mp.code)
-			kn.Style = mp.style
-
-			m.Content = append(m.Content, kn)
-			m.Content = append(m.Content, rendered.(*yaml.Node))
-		}
-		if mp.ext != nil {
-			m.Content = append(m.Content, utils.CreateStringNode(mp.code))
-			m.Content = append(m.Content, mp.ext)
-		}
-
-	}
-	return m, nil
+	return r.MarshalYAMLWithOptions(high.RenderOptions{})
 }
 
 func (r *Responses) MarshalYAMLInline() (interface{}, error) {
+	return r.MarshalYAMLWithOptions(high.RenderOptions{Resolve: true})
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Responses object, using the
+// options of the NodeBuilder rendering it.
+func (r *Responses) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
 	// map keys correctly.
 	m := utils.CreateEmptyMapNode()
 	type responseItem struct {
-		resp  *Response
-		code  string
-		line  int
-		ext   *yaml.Node
-		style yaml.Style
+		resp    *Response
+		code    string
+		line    int
+		ext     *yaml.Node
+		style   yaml.Style
+		keyNode *yaml.Node
 	}
 	var mapped []*responseItem
 
 	for code, resp := range r.Codes.FromOldest() {
-		ln := 9999 // default to a high value to weight new content to the bottom.
+		ln := 9999 + len(mapped) // default to a high value to weight new content to the bottom, in the order it was added.
 		var style yaml.Style
+		var keyNode *yaml.Node
 		if r.low != nil {
 			for lk := range r.low.Codes.KeysFromOldest() {
 				if lk.Value == code {
 					ln = lk.KeyNode.Line
 					style = lk.KeyNode.Style
+					keyNode = lk.KeyNode
 				}
 			}
 		}
-		mapped = append(mapped, &responseItem{resp, code, ln, nil, style})
+		mapped = append(mapped, &responseItem{resp, code, ln, nil, style, keyNode})
 	}
 
 	// extract extensions
-	nb := high.NewNodeBuilder(r, r.low)
-	nb.Resolve = true
+	nb := high.NewNodeBuilderWithOptions(r, r.low, opts)
 	extNode := nb.Render()
 	if extNode != nil && extNode.Content != nil {
 		var label string
@@ -193,27 +140,32 @@ func (r *Responses) MarshalYAMLInline() (interface{}, error) {
 			}
 			mapped = append(mapped, &responseItem{
 				nil, label,
-				extNode.Content[u].Line, extNode.Content[u], 0,
+				extNode.Content[u].Line, extNode.Content[u], 0, extNode.Content[u-1],
 			})
 		}
 	}
 
-	sort.Slice(mapped, func(i, j int) bool {
+	sort.SliceStable(mapped, func(i, j int) bool {
 		return mapped[i].line < mapped[j].line
 	})
 	for _, mp := range mapped {
 		if mp.resp != nil {
-			rendered, _ := mp.resp.MarshalYAMLInline()
+			rendered, _ := mp.resp.MarshalYAMLWithOptions(opts.Child(mp.code))
 
 			kn := utils.CreateStringNode(mp.code)
 			kn.Style = mp.style
+			utils.CopyComments(mp.keyNode, kn)
+			if c := opts.Comment(mp.code); c != "" {
+				kn.HeadComment = c
+			}
 
 			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, rendered.(*yaml.Node))
-
 		}
 		if mp.ext != nil {
-			m.Content = append(m.Content, utils.CreateStringNode(mp.code))
+			kn := utils.CreateStringNode(mp.code)
+			utils.CopyComments(mp.keyNode, kn)
+			m.Content = append(m.Content, kn)
 			m.Content = append(m.Content, mp.ext)
 		}
 
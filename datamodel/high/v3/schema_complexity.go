@@ -0,0 +1,48 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"sort"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// SchemaComplexity holds the name of a component schema and the metrics calculated for it.
+type SchemaComplexity struct {
+	Name    string             `json:"name" yaml:"name"`
+	Metrics base.SchemaMetrics `json:"metrics" yaml:"metrics"`
+}
+
+// SchemaComplexityReport will calculate metrics for every component schema and return them ranked from the most
+// complex to the least complex. Schemas are ranked by depth, then total property count, then reference fan-out.
+// Schemas with the same scores keep the order they are defined in.
+func (d *Document) SchemaComplexityReport() []*SchemaComplexity {
+	if d.Components == nil || d.Components.Schemas == nil {
+		return nil
+	}
+	var report []*SchemaComplexity
+	for name, proxy := range d.Components.Schemas.FromOldest() {
+		schema := proxy.Schema()
+		if schema == nil {
+			continue
+		}
+		report = append(report, &SchemaComplexity{
+			Name:    name,
+			Metrics: schema.MetricsForReference("#/components/schemas/" + utils.EscapeJSONPointer(name)),
+		})
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i].Metrics, report[j].Metrics
+		if a.Depth != b.Depth {
+			return a.Depth > b.Depth
+		}
+		if a.TotalProperties != b.TotalProperties {
+			return a.TotalProperties > b.TotalProperties
+		}
+		return a.ReferenceFanOut > b.ReferenceFanOut
+	})
+	return report
+}
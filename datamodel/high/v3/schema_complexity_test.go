@@ -0,0 +1,140 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_SchemaComplexityReport(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Simple:
+      type: string
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+        address:
+          type: object
+          properties:
+            street:
+              type: string
+            city:
+              type: string
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Owner'
+        tags:
+          type: array
+          items:
+            $ref: '#/components/schemas/Simple'
+    Node:
+      type: object
+      properties:
+        value:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'`
+
+	d := buildTestDocument(t, spec)
+	report := d.SchemaComplexityReport()
+	assert.Len(t, report, 4)
+
+	// Pet -> owner (Owner) -> address -> street
+	assert.Equal(t, "Pet", report[0].Name)
+	assert.Equal(t, 4, report[0].Metrics.Depth)
+	assert.Equal(t, 3, report[0].Metrics.Properties)
+	assert.Equal(t, 7, report[0].Metrics.TotalProperties)
+	assert.Equal(t, 2, report[0].Metrics.ReferenceFanOut)
+	assert.False(t, report[0].Metrics.Circular)
+
+	assert.Equal(t, "Owner", report[1].Name)
+	assert.Equal(t, 3, report[1].Metrics.Depth)
+	assert.Equal(t, 2, report[1].Metrics.Properties)
+	assert.Equal(t, 4, report[1].Metrics.TotalProperties)
+	assert.Equal(t, 0, report[1].Metrics.ReferenceFanOut)
+
+	// the circular reference back to Node is not followed, so it adds no depth.
+	assert.Equal(t, "Node", report[2].Name)
+	assert.Equal(t, 2, report[2].Metrics.Depth)
+	assert.Equal(t, 2, report[2].Metrics.TotalProperties)
+	assert.Equal(t, 1, report[2].Metrics.ReferenceFanOut)
+	assert.True(t, report[2].Metrics.Circular)
+
+	assert.Equal(t, "Simple", report[3].Name)
+	assert.Equal(t, 1, report[3].Metrics.Depth)
+	assert.Equal(t, 0, report[3].Metrics.TotalProperties)
+}
+
+func TestSchema_Metrics_Circular(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    A:
+      type: object
+      properties:
+        b:
+          $ref: '#/components/schemas/B'
+    B:
+      type: object
+      properties:
+        a:
+          $ref: '#/components/schemas/A'`
+
+	d := buildTestDocument(t, spec)
+	a := d.Components.Schemas.GetOrZero("A").Schema()
+
+	// without a starting reference, A is walked once more via B before the cycle is found.
+	m := a.Metrics()
+	assert.True(t, m.Circular)
+	assert.Equal(t, 3, m.Depth)
+	assert.Equal(t, 3, m.TotalProperties)
+	assert.Equal(t, 2, m.ReferenceFanOut)
+
+	m = a.MetricsForReference("#/components/schemas/A")
+	assert.True(t, m.Circular)
+	assert.Equal(t, 2, m.Depth)
+	assert.Equal(t, 2, m.TotalProperties)
+}
+
+func TestDocument_SchemaComplexityReport_EscapedName(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    v1/Node:
+      type: object
+      properties:
+        value:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/v1~1Node'`
+
+	d := buildTestDocument(t, spec)
+	report := d.SchemaComplexityReport()
+	assert.Len(t, report, 1)
+
+	// the reference back to the schema is recognised, so it is not walked again.
+	assert.Equal(t, "v1/Node", report[0].Name)
+	assert.True(t, report[0].Metrics.Circular)
+	assert.Equal(t, 2, report[0].Metrics.Depth)
+	assert.Equal(t, 2, report[0].Metrics.TotalProperties)
+}
+
+func TestDocument_SchemaComplexityReport_NoComponents(t *testing.T) {
+	d := &Document{}
+	assert.Nil(t, d.SchemaComplexityReport())
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// SchemaExampleWarning describes a schema that uses the deprecated singular `example` keyword.
+type SchemaExampleWarning struct {
+	Path    string `json:"path" yaml:"path"`
+	Line    int    `json:"line" yaml:"line"`
+	Column  int    `json:"column" yaml:"column"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// SingularSchemaExamples will look through all component schemas (and their inline sub-schemas) of an OpenAPI 3.1
+// document and return a warning for every schema that uses the singular `example` keyword, which is deprecated in
+// 3.1 in favor of `examples`. Documents that are not 3.1 are not checked, `example` is valid in 3.0.
+//
+// Use base.Schema.MigrateExampleToExamples to fix a reported schema.
+func (d *Document) SingularSchemaExamples() []*SchemaExampleWarning {
+	if !strings.HasPrefix(d.Version, "3.1") || d.Components == nil || d.Components.Schemas == nil {
+		return nil
+	}
+	var warnings []*SchemaExampleWarning
+	for name, proxy := range d.Components.Schemas.FromOldest() {
+		warnings = append(warnings, singularSchemaExamples(proxy, "#/components/schemas/"+utils.EscapeJSONPointer(name))...)
+	}
+	return warnings
+}
+
+func singularSchemaExamples(proxy *base.SchemaProxy, path string) []*SchemaExampleWarning {
+	// references are checked where they are defined.
+	if proxy == nil || proxy.IsReference() {
+		return nil
+	}
+	schema := proxy.Schema()
+	if schema == nil {
+		return nil
+	}
+	var warnings []*SchemaExampleWarning
+	if schema.Example != nil {
+		w := &SchemaExampleWarning{
+			Path:    path,
+			Message: fmt.Sprintf("schema '%s' uses 'example', which is deprecated in OpenAPI 3.1, use 'examples' instead", path),
+		}
+		if low := schema.GoLow(); low != nil && low.Example.KeyNode != nil {
+			w.Line = low.Example.KeyNode.Line
+			w.Column = low.Example.KeyNode.Column
+		}
+		warnings = append(warnings, w)
+	}
+	if schema.Properties != nil {
+		for name, p := range schema.Properties.FromOldest() {
+			warnings = append(warnings, singularSchemaExamples(p, path+"/properties/"+utils.EscapeJSONPointer(name))...)
+		}
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		warnings = append(warnings, singularSchemaExamples(schema.Items.A, path+"/items")...)
+	}
+	for i, p := range schema.AllOf {
+		warnings = append(warnings, singularSchemaExamples(p, fmt.Sprintf("%s/allOf/%d", path, i))...)
+	}
+	for i, p := range schema.AnyOf {
+		warnings = append(warnings, singularSchemaExamples(p, fmt.Sprintf("%s/anyOf/%d", path, i))...)
+	}
+	for i, p := range schema.OneOf {
+		warnings = append(warnings, singularSchemaExamples(p, fmt.Sprintf("%s/oneOf/%d", path, i))...)
+	}
+	return warnings
+}
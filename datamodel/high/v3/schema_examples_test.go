@@ -0,0 +1,97 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_SingularSchemaExamples_Migrate(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      example:
+        name: fluffy
+      properties:
+        name:
+          type: string
+          example: fluffy
+        age:
+          type: integer
+          examples:
+            - 3`
+
+	d := buildTestDocument(t, spec)
+	warnings := d.SingularSchemaExamples()
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, "#/components/schemas/Pet", warnings[0].Path)
+	assert.Equal(t, 6, warnings[0].Line)
+	assert.Equal(t, 7, warnings[0].Column)
+	assert.Equal(t, "#/components/schemas/Pet/properties/name", warnings[1].Path)
+	assert.Equal(t, 11, warnings[1].Line)
+
+	pet := d.Components.Schemas.GetOrZero("Pet").Schema()
+	assert.True(t, pet.MigrateExampleToExamples())
+	assert.False(t, pet.MigrateExampleToExamples())
+	assert.True(t, pet.Properties.GetOrZero("name").Schema().MigrateExampleToExamples())
+	assert.False(t, pet.Properties.GetOrZero("age").Schema().MigrateExampleToExamples())
+	assert.Nil(t, pet.Example)
+	assert.Len(t, pet.Examples, 1)
+	assert.Empty(t, d.SingularSchemaExamples())
+
+	rendered, err := d.Render()
+	assert.NoError(t, err)
+
+	// the migrated document must round-trip with the examples intact.
+	info, _ := datamodel.ExtractSpecInfo(rendered)
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	reloaded := NewDocument(lDoc)
+	assert.Empty(t, reloaded.SingularSchemaExamples())
+
+	rPet := reloaded.Components.Schemas.GetOrZero("Pet").Schema()
+	assert.Nil(t, rPet.Example)
+	assert.Len(t, rPet.Examples, 1)
+	assert.Equal(t, "fluffy", rPet.Examples[0].Content[1].Value)
+
+	rName := rPet.Properties.GetOrZero("name").Schema()
+	assert.Nil(t, rName.Example)
+	assert.Len(t, rName.Examples, 1)
+	assert.Equal(t, "fluffy", rName.Examples[0].Value)
+}
+
+func TestDocument_SingularSchemaExamples_EscapedPath(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    v1/Pet:
+      type: object
+      properties:
+        a~b:
+          type: string
+          example: fluffy`
+
+	d := buildTestDocument(t, spec)
+	warnings := d.SingularSchemaExamples()
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "#/components/schemas/v1~1Pet/properties/a~0b", warnings[0].Path)
+}
+
+func TestDocument_SingularSchemaExamples_NotChecked30(t *testing.T) {
+	spec := `openapi: 3.0.3
+components:
+  schemas:
+    Pet:
+      type: string
+      example: fluffy`
+
+	d := buildTestDocument(t, spec)
+	assert.Empty(t, d.SingularSchemaExamples())
+}
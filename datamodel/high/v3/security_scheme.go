@@ -66,8 +66,30 @@ func (s *SecurityScheme) Render() ([]byte, error) {
 	return yaml.Marshal(s)
 }
 
+// RenderInline will return a YAML representation of the SecurityScheme object as a byte slice, with all references
+// rendered inline.
+func (s *SecurityScheme) RenderInline() ([]byte, error) {
+	d, _ := s.MarshalYAMLInline()
+	return yaml.Marshal(d)
+}
+
 // MarshalYAML will create a ready to render YAML representation of the Response object.
 func (s *SecurityScheme) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(s, s.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLInline will create a ready to render YAML representation of the SecurityScheme object, with all references
+// rendered inline.
+func (s *SecurityScheme) MarshalYAMLInline() (interface{}, error) {
+	nb := high.NewNodeBuilder(s, s.low)
+	nb.Resolve = true
+	return nb.Render(), nil
+}
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the SecurityScheme object, using the
+// options of the NodeBuilder rendering it.
+func (s *SecurityScheme) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
+	return nb.Render(), nil
+}
@@ -52,3 +52,10 @@ func (s *Server) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(s, s.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the Server object, using the options of
+// the NodeBuilder rendering it.
+func (s *Server) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
+	return nb.Render(), nil
+}
@@ -59,3 +59,10 @@ func (s *ServerVariable) MarshalYAML() (interface{}, error) {
 	nb := high.NewNodeBuilder(s, s.low)
 	return nb.Render(), nil
 }
+
+// MarshalYAMLWithOptions will create a ready to render YAML representation of the ServerVariable object, using the
+// options of the NodeBuilder rendering it.
+func (s *ServerVariable) MarshalYAMLWithOptions(opts high.RenderOptions) (interface{}, error) {
+	nb := high.NewNodeBuilderWithOptions(s, s.low, opts)
+	return nb.Render(), nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// SkipChildren can be returned from a Walk visitor to skip the children of the current node, without
+// stopping the walk.
+var SkipChildren = errors.New("skip children")
+
+// VisitFunc is called by Walk for every high-level object in a Document. The path is a JSON pointer
+// to the object, for example '#/paths/~1pets/get'.
+type VisitFunc func(path string, node any) error
+
+var (
+	schemaProxyType = reflect.TypeOf((*base.SchemaProxy)(nil))
+	yamlNodeType    = reflect.TypeOf((*yaml.Node)(nil))
+)
+
+const highPackagePrefix = "github.com/pb33f/libopenapi/datamodel/high"
+
+// Walk performs a depth-first traversal of a high-level Document, calling visit for the document and
+// every high-level object within it, in document order. Schemas are visited (rather than their proxies),
+// and each object is visited only once, even when it can be reached by more than one path (for example
+// a schema referenced inline and defined in components). The walk stops at the first error returned by
+// visit, which is returned by Walk, unless that error is SkipChildren.
+//
+// Walk is read-only, it never modifies the document.
+func Walk(doc *Document, visit VisitFunc) error {
+	if doc == nil {
+		return nil
+	}
+	w := &walker{
+		visit:   visit,
+		seen:    make(map[any]bool),
+		schemas: make(map[*yaml.Node]bool),
+	}
+	return w.walk("#", reflect.ValueOf(doc))
+}
+
+type walker struct {
+	visit   VisitFunc
+	seen    map[any]bool
+	schemas map[*yaml.Node]bool
+}
+
+func (w *walker) walk(path string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return w.walk(path, v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(path+"/"+strconv.Itoa(i), v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == yamlNodeType {
+			return nil
+		}
+		if v.Type() == schemaProxyType {
+			return w.walkSchema(path, v.Interface().(*base.SchemaProxy))
+		}
+		if isOrderedMap(v) {
+			return w.walkMap(path, v)
+		}
+		if v.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		if !strings.HasPrefix(v.Elem().Type().PkgPath(), highPackagePrefix) {
+			return nil
+		}
+		return w.walkObject(path, v)
+	case reflect.Struct:
+		// value structs (such as DynamicValue) are not objects in their own right, their fields are.
+		return w.walkFields(path, v)
+	}
+	return nil
+}
+
+func (w *walker) walkSchema(path string, proxy *base.SchemaProxy) error {
+	schema := proxy.Schema()
+	if schema == nil {
+		return nil
+	}
+	// reference proxies render their own copy of a schema, so use the low-level root node to
+	// identify schemas that have already been visited.
+	if low := schema.GoLow(); low != nil && low.RootNode != nil {
+		if w.schemas[low.RootNode] {
+			return nil
+		}
+		w.schemas[low.RootNode] = true
+	}
+	return w.walkObject(path, reflect.ValueOf(schema))
+}
+
+func (w *walker) walkObject(path string, v reflect.Value) error {
+	node := v.Interface()
+	if w.seen[node] {
+		return nil
+	}
+	w.seen[node] = true
+
+	if err := w.visit(path, node); err != nil {
+		if errors.Is(err, SkipChildren) {
+			return nil
+		}
+		return err
+	}
+	return w.walkFields(path, v.Elem())
+}
+
+func (w *walker) walkFields(path string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Name == "Extensions" {
+			continue
+		}
+		fieldPath := path
+		if tag, ok := field.Tag.Lookup("yaml"); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				// maps that are inlined into their parent (paths, responses, callbacks) have no segment of
+				// their own, everything else that is not rendered is not part of the tree.
+				if !isOrderedMap(v.Field(i)) {
+					continue
+				}
+			} else {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = path + "/" + utils.EscapeJSONPointer(name)
+			}
+		}
+		if err := w.walk(fieldPath, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkMap(path string, v reflect.Value) error {
+	for pair := v.MethodByName("First").Call(nil)[0]; !pair.IsNil(); pair = pair.MethodByName("Next").Call(nil)[0] {
+		key := pair.MethodByName("Key").Call(nil)[0]
+		value := pair.MethodByName("Value").Call(nil)[0]
+		segment := utils.EscapeJSONPointer(fmt.Sprint(key.Interface()))
+		if err := w.walk(path+"/"+segment, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isOrderedMap(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.Type().Elem().PkgPath() == "github.com/pb33f/libopenapi/orderedmap" &&
+		v.MethodByName("First").IsValid()
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var walkSpec = `openapi: 3.1.0
+info:
+  title: walk
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          schema:
+            type: string
+      responses:
+        "200":
+          description: a pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    delete:
+      operationId: deletePet
+      responses:
+        "204":
+          description: deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string`
+
+func newWalkDocument(t *testing.T) *Document {
+	info, _ := datamodel.ExtractSpecInfo([]byte(walkSpec))
+	lDoc, err := lowv3.CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	return NewDocument(lDoc)
+}
+
+func TestWalk(t *testing.T) {
+	h := newWalkDocument(t)
+
+	paths := make(map[string]any)
+	var operationIds []string
+	err := Walk(h, func(path string, node any) error {
+		paths[path] = node
+		if op, ok := node.(*Operation); ok {
+			operationIds = append(operationIds, op.OperationId)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"getPet", "deletePet"}, operationIds)
+
+	assert.Equal(t, h, paths["#"])
+	assert.Equal(t, h.Info, paths["#/info"])
+	assert.IsType(t, &PathItem{}, paths["#/paths/~1pets~1{id}"])
+	assert.IsType(t, &Parameter{}, paths["#/paths/~1pets~1{id}/get/parameters/0"])
+	assert.IsType(t, &base.Schema{}, paths["#/paths/~1pets~1{id}/get/parameters/0/schema"])
+	assert.IsType(t, &Response{}, paths["#/paths/~1pets~1{id}/get/responses/200"])
+	assert.IsType(t, &base.Schema{}, paths["#/paths/~1pets~1{id}/get/responses/200/content/application~1json/schema/properties/tags/items"])
+
+	// Pet is reached through the response first, so it is not visited again from components.
+	assert.Contains(t, paths, "#/paths/~1pets~1{id}/get/responses/200/content/application~1json/schema")
+	assert.NotContains(t, paths, "#/components/schemas/Pet")
+	assert.Contains(t, paths, "#/components")
+}
+
+func TestWalk_SchemasVisitedOnce(t *testing.T) {
+	h := newWalkDocument(t)
+
+	titles := make(map[string]int)
+	err := Walk(h, func(path string, node any) error {
+		if s, ok := node.(*base.Schema); ok && len(s.Type) > 0 && s.Type[0] == "object" {
+			titles[path]++
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, titles, 1)
+}
+
+func TestWalk_SkipChildren(t *testing.T) {
+	h := newWalkDocument(t)
+
+	var visited []string
+	err := Walk(h, func(path string, node any) error {
+		visited = append(visited, path)
+		if _, ok := node.(*Paths); ok {
+			return SkipChildren
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, visited, "#/paths")
+	assert.Contains(t, visited, "#/components/schemas/Pet")
+	for _, p := range visited {
+		assert.NotContains(t, p, "#/paths/")
+	}
+}
+
+func TestWalk_Error(t *testing.T) {
+	h := newWalkDocument(t)
+
+	stop := errors.New("stop")
+	err := Walk(h, func(path string, node any) error {
+		if _, ok := node.(*Operation); ok {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+
+	var operations int
+	_ = Walk(h, func(path string, node any) error {
+		if _, ok := node.(*Operation); ok {
+			operations++
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, 1, operations)
+}
+
+func TestWalk_NilDocument(t *testing.T) {
+	assert.NoError(t, Walk(nil, func(path string, node any) error {
+		return errors.New("should not be called")
+	}))
+}
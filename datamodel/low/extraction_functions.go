@@ -102,6 +102,13 @@ func LocateRefNodeWithContext(ctx context.Context, root *yaml.Node, idx *index.S
 							found[rv].Node.Column), ctx
 					}
 				}
+				// a reference into another file is built against that file's index and location, so any
+				// relative references it contains are resolved from there.
+				if found[rv].RemoteLocation != "" && found[rv].RemoteLocation != idx.GetSpecAbsolutePath() {
+					if fRef, fIdx, fCtx := idx.SearchIndexForReferenceWithContext(ctx, rv); fRef != nil && fIdx != nil {
+						return utils.NodeAlias(found[rv].Node), fIdx, nil, fCtx
+					}
+				}
 				return utils.NodeAlias(found[rv].Node), idx, nil, ctx
 			}
 		}
@@ -115,9 +122,9 @@ func LocateRefNodeWithContext(ctx context.Context, root *yaml.Node, idx *index.S
 
 		explodedRefValue := strings.Split(rv, "#")
 		if len(explodedRefValue) == 2 {
-			if !strings.HasPrefix(explodedRefValue[0], "http") {
+			if !utils.IsRemoteLocation(explodedRefValue[0]) {
 				if !filepath.IsAbs(explodedRefValue[0]) {
-					if strings.HasPrefix(specPath, "http") {
+					if utils.IsRemoteLocation(specPath) {
 						u, _ := url.Parse(specPath)
 						p := ""
 						if u.Path != "" && explodedRefValue[0] != "" {
@@ -157,9 +164,9 @@ func LocateRefNodeWithContext(ctx context.Context, root *yaml.Node, idx *index.S
 				}
 			}
 		} else {
-			if !strings.HasPrefix(explodedRefValue[0], "http") {
+			if !utils.IsRemoteLocation(explodedRefValue[0]) {
 				if !filepath.IsAbs(explodedRefValue[0]) {
-					if strings.HasPrefix(specPath, "http") {
+					if utils.IsRemoteLocation(specPath) {
 						u, _ := url.Parse(specPath)
 						p := filepath.Dir(u.Path)
 						abs, _ := filepath.Abs(filepath.Join(p, rv))
@@ -21,6 +21,7 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/low/base"
 	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -142,19 +143,49 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	idxConfig := index.CreateClosedAPIIndexConfig()
 	idxConfig.SpecInfo = info
 	idxConfig.IgnoreArrayCircularReferences = config.IgnoreArrayCircularReferences
+	idxConfig.MaxResolveDepth = config.MaxResolveDepth
 	idxConfig.IgnorePolymorphicCircularReferences = config.IgnorePolymorphicCircularReferences
 	idxConfig.AvoidCircularReferenceCheck = true
 	idxConfig.BaseURL = config.BaseURL
 	idxConfig.BasePath = config.BasePath
+	// if only the location of the root file is known, relative references are resolved from its directory.
+	if idxConfig.BasePath == "" && config.RootFilePath != "" {
+		idxConfig.BasePath = filepath.Dir(utils.ReplaceWindowsDriveWithLinuxPath(config.RootFilePath))
+	}
+	idxConfig.HTTPClient = config.HTTPClient
+	idxConfig.RemoteCacheDir = config.RemoteCacheDir
+	idxConfig.RemoteRetries = config.RemoteRetries
+	idxConfig.RemoteRetryBackoff = config.RemoteRetryBackoff
+	idxConfig.RemoteRetryMaxWait = config.RemoteRetryMaxWait
 	idxConfig.Logger = config.Logger
 	rolodex := index.NewRolodex(idxConfig)
 	rolodex.SetRootNode(info.RootNode)
 	doc.Rolodex = rolodex
 
+	// if virtual files are provided, they replace the local and remote file systems.
+	if config.VirtualFS != nil {
+		cwd, _ := filepath.Abs(idxConfig.BasePath)
+		memoryFS := index.NewMemoryFS(config.VirtualFS)
+		idxConfig.AllowFileLookup = true
+		fileFS, err := index.NewLocalFSWithConfig(&index.LocalFSConfig{
+			BaseDirectory: cwd,
+			IndexConfig:   idxConfig,
+			FileFilters:   config.FileFilter,
+			DirFS:         memoryFS,
+		})
+		if err == nil {
+			rolodex.AddLocalFS(cwd, fileFS)
+		}
+		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
+		remoteFS.RemoteHandlerFunc = memoryFS.RemoteHandler
+		idxConfig.AllowRemoteLookup = true
+		rolodex.AddRemoteFS("virtual", remoteFS)
+	}
+
 	// If basePath is provided, add a local filesystem to the rolodex.
-	if idxConfig.BasePath != "" {
+	if config.VirtualFS == nil && idxConfig.BasePath != "" {
 		var cwd string
-		cwd, _ = filepath.Abs(config.BasePath)
+		cwd, _ = filepath.Abs(idxConfig.BasePath)
 		// if a supplied local filesystem is provided, add it to the rolodex.
 		if config.LocalFS != nil {
 			rolodex.AddLocalFS(cwd, config.LocalFS)
@@ -175,18 +206,23 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	}
 
 	// if base url is provided, add a remote filesystem to the rolodex.
-	if idxConfig.BaseURL != nil {
-
-		// create a remote filesystem
-		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
-		if config.RemoteURLHandler != nil {
-			remoteFS.RemoteHandlerFunc = config.RemoteURLHandler
-		}
+	if config.VirtualFS == nil && idxConfig.BaseURL != nil {
 		idxConfig.AllowRemoteLookup = true
 
-		// add to the rolodex
-		rolodex.AddRemoteFS(config.BaseURL.String(), remoteFS)
+		// if a supplied remote filesystem is provided, add it to the rolodex.
+		if config.RemoteFS != nil {
+			rolodex.AddRemoteFS(config.BaseURL.String(), config.RemoteFS)
+		} else {
 
+			// create a remote filesystem
+			remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
+			if config.RemoteURLHandler != nil {
+				remoteFS.RemoteHandlerFunc = config.RemoteURLHandler
+			}
+
+			// add to the rolodex
+			rolodex.AddRemoteFS(config.BaseURL.String(), remoteFS)
+		}
 	}
 
 	doc.Rolodex = rolodex
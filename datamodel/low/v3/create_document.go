@@ -3,7 +3,10 @@ package v3
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,15 +23,56 @@ import (
 // Deprecated: Use CreateDocumentFromConfig instead. This function will be removed in a later version, it
 // defaults to allowing file and remote references, and does not support relative file references.
 func CreateDocument(info *datamodel.SpecInfo) (*Document, error) {
-	return createDocument(info, datamodel.NewDocumentConfiguration())
+	return createDocument(context.Background(), info, datamodel.NewDocumentConfiguration())
 }
 
 // CreateDocumentFromConfig Create a new document from the provided SpecInfo and DocumentConfiguration pointer.
 func CreateDocumentFromConfig(info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration) (*Document, error) {
-	return createDocument(info, config)
+	return createDocument(context.Background(), info, config)
 }
 
-func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration) (*Document, error) {
+// CreateDocumentFromConfigWithContext works the same way as CreateDocumentFromConfig, but threads the supplied
+// context through every extraction and Build call. This is used for both OpenAPI 3.0 and 3.1 documents.
+// Values stored in the context are available to all models as they are built, and if the context is
+// cancelled, extraction stops and the context error is returned. The context is also used for remote references,
+// so a deadline bounds the total time spent fetching them, and cancels requests that are still in-flight.
+func CreateDocumentFromConfigWithContext(ctx context.Context, info *datamodel.SpecInfo,
+	config *datamodel.DocumentConfiguration,
+) (*Document, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return createDocument(ctx, info, config)
+}
+
+// CreatePartialDocument works the same way as CreateDocumentFromConfig, but only extracts the requested sections
+// of the document, the rest are left empty. This avoids the cost of building parts of a large specification that
+// are not needed, for example only extracting 'paths' to enumerate the operations.
+//
+// Sections are named after the document fields they extract: 'info', 'servers', 'tags', 'components', 'security',
+// 'externalDocs', 'paths' and 'webhooks'. If no sections are supplied, the whole document is extracted. The
+// version, extensions and jsonSchemaDialect are always extracted, as are any ExtraExtractors. The whole
+// specification is still indexed, so references can be resolved across sections.
+func CreatePartialDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration,
+	sections ...string,
+) (*Document, error) {
+	return createPartialDocument(context.Background(), info, config, sections)
+}
+
+func createDocument(parent context.Context, info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration) (*Document, error) {
+	return createPartialDocument(parent, info, config, nil)
+}
+
+func createPartialDocument(parent context.Context, info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration,
+	sections []string,
+) (*Document, error) {
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	builtIn, err := selectExtractionStages(sections)
+	if err != nil {
+		return nil, err
+	}
 	_, labelNode, versionNode := utils.FindKeyNodeFull(OpenAPILabel, info.RootNode.Content)
 	var version low.NodeReference[string]
 	if versionNode == nil {
@@ -37,65 +81,41 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	version = low.NodeReference[string]{Value: versionNode.Value, KeyNode: labelNode, ValueNode: versionNode}
 	doc := Document{Version: version}
 	doc.Nodes = low.ExtractNodes(nil, info.RootNode.Content[0])
-	// create an index config and shadow the document configuration.
-	idxConfig := index.CreateClosedAPIIndexConfig()
-	idxConfig.SpecInfo = info
-	idxConfig.IgnoreArrayCircularReferences = config.IgnoreArrayCircularReferences
-	idxConfig.IgnorePolymorphicCircularReferences = config.IgnorePolymorphicCircularReferences
-	idxConfig.AvoidCircularReferenceCheck = true
-	idxConfig.BaseURL = config.BaseURL
-	idxConfig.BasePath = config.BasePath
-	idxConfig.SpecFilePath = config.SpecFilePath
-	idxConfig.Logger = config.Logger
-	extract := config.ExtractRefsSequentially
-	idxConfig.ExtractRefsSequentially = extract
-	rolodex := index.NewRolodex(idxConfig)
-	rolodex.SetRootNode(info.RootNode)
-	doc.Rolodex = rolodex
-
-	// If basePath is provided, add a local filesystem to the rolodex.
-	if idxConfig.BasePath != "" || config.AllowFileReferences {
-		var cwd string
-		cwd, _ = filepath.Abs(config.BasePath)
-		// if a supplied local filesystem is provided, add it to the rolodex.
-		if config.LocalFS != nil {
-			rolodex.AddLocalFS(cwd, config.LocalFS)
-		} else {
-
-			// create a local filesystem
-			localFSConf := index.LocalFSConfig{
-				BaseDirectory: cwd,
-				IndexConfig:   idxConfig,
-				FileFilters:   config.FileFilter,
-			}
-
-			fileFS, _ := index.NewLocalFSWithConfig(&localFSConf)
-			idxConfig.AllowFileLookup = true
 
-			// add the filesystem to the rolodex
-			rolodex.AddLocalFS(cwd, fileFS)
-		}
+	// constructs that don't belong to the declared version are extracted anyway, so report them.
+	if config.StrictVersionCheck {
+		doc.Warnings = append(doc.Warnings, checkVersionConstructs(version.Value, info.RootNode)...)
 	}
-	// if base url is provided, add a remote filesystem to the rolodex.
-	if idxConfig.BaseURL != nil || config.AllowRemoteReferences {
 
-		// create a remote filesystem
-		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
-		if config.RemoteURLHandler != nil {
-			remoteFS.RemoteHandlerFunc = config.RemoteURLHandler
+	// duplicate keys are checked on the raw tree, before the decoder has a chance to drop anything.
+	var duplicateKeys []error
+	if config.DetectDuplicateKeys || config.DuplicateKeysAreErrors {
+		for _, d := range utils.FindDuplicateKeys(info.RootNode) {
+			duplicateKeys = append(duplicateKeys, d)
 		}
-		idxConfig.AllowRemoteLookup = true
-
-		// add to the rolodex
-		u := "default"
-		if config.BaseURL != nil {
-			u = config.BaseURL.String()
-		}
-		rolodex.AddRemoteFS(u, remoteFS)
 	}
+	rolodex := newRolodex(parent, info, config)
+	doc.Rolodex = rolodex
 
 	// index the rolodex
 	var errs []error
+	if config.DuplicateKeysAreErrors {
+		errs = append(errs, duplicateKeys...)
+	} else {
+		doc.Warnings = append(doc.Warnings, duplicateKeys...)
+	}
+	stages := builtIn
+	if config.SchemaDialectValidator != nil {
+		for i := range stages {
+			if stages[i].name == "components" {
+				stages[i] = schemaDialectStage(stages[i], config.SchemaDialectValidator, config.SchemaDialectErrors)
+			}
+		}
+	}
+	for _, x := range config.ExtraExtractors {
+		stages = append(stages, extraExtractionStage(x))
+	}
+	progress := &progressReporter{fn: config.ProgressFunc, total: 2 + len(stages)}
 
 	// index all the things.
 	if config.Logger != nil {
@@ -107,6 +127,7 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	if config.Logger != nil {
 		config.Logger.Debug("rolodex indexed", "ms", done)
 	}
+	progress.complete("index")
 	// check for circular references
 	if config.Logger != nil {
 		config.Logger.Debug("checking for circular references")
@@ -121,19 +142,23 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 			config.Logger.Debug("circular check completed", "ms", done)
 		}
 	}
+	progress.complete("circular references")
 	// extract errors
 	roloErrs := rolodex.GetCaughtErrors()
 	if roloErrs != nil {
 		errs = append(errs, roloErrs...)
 	}
 
+	// ignored circular references are not errors, but they are worth knowing about.
+	doc.Warnings = append(doc.Warnings, ignoredCircularWarnings(rolodex.GetIgnoredCircularReferences())...)
+
 	// set root index.
 	doc.Index = rolodex.GetRootIndex()
 	var wg sync.WaitGroup
 
 	var cacheMap sync.Map
 	modelContext := base.ModelContext{SchemaCache: &cacheMap}
-	ctx := context.WithValue(context.Background(), "modelCtx", &modelContext)
+	ctx := context.WithValue(parent, "modelCtx", &modelContext)
 
 	doc.Extensions = low.ExtractExtensions(info.RootNode.Content[0])
 	low.ExtractExtensionNodes(ctx, doc.Extensions, doc.Nodes)
@@ -146,36 +171,34 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 		}
 	}
 
+	// each stage writes its error into its own slot, so there is nothing shared to race on, and errors are always
+	// returned in stage order, no matter which stage finishes first.
+	stageErrs := make([]error, len(stages))
 	runExtraction := func(ctx context.Context, info *datamodel.SpecInfo, doc *Document, idx *index.SpecIndex,
-		runFunc func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error,
-		ers *[]error,
+		stage extractionStage,
+		er *error,
 		wg *sync.WaitGroup,
 	) {
-		if er := runFunc(ctx, info, doc, idx); er != nil {
-			*ers = append(*ers, er)
-		}
+		*er = stage.run(ctx, info, doc, idx)
+		progress.complete(stage.name)
 		wg.Done()
 	}
-	extractionFuncs := []func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error{
-		extractInfo,
-		extractServers,
-		extractTags,
-		extractComponents,
-		extractSecurity,
-		extractExternalDocs,
-		extractPaths,
-		extractWebhooks,
-	}
 
-	wg.Add(len(extractionFuncs))
 	if config.Logger != nil {
 		config.Logger.Debug("running extractions")
 	}
 	now = time.Now()
-	for _, f := range extractionFuncs {
-		runExtraction(ctx, info, &doc, rolodex.GetRootIndex(), f, &errs, &wg)
+	var ctxErr error
+	for i, f := range stages {
+		// stop extracting if the caller has given up.
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			break
+		}
+		wg.Add(1)
+		runExtraction(ctx, info, &doc, rolodex.GetRootIndex(), f, &stageErrs[i], &wg)
 	}
 	wg.Wait()
+	errs = append(append(errs, stageErrs...), ctxErr)
 	done = time.Duration(time.Since(now).Milliseconds())
 	if config.Logger != nil {
 		config.Logger.Debug("extractions complete", "time", done)
@@ -183,6 +206,195 @@ func createDocument(info *datamodel.SpecInfo, config *datamodel.DocumentConfigur
 	return &doc, errors.Join(errs...)
 }
 
+// newRolodex creates a rolodex for the specification, configured from the document configuration, with local and
+// remote file systems added as required. The rolodex is not indexed.
+func newRolodex(parent context.Context, info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration) *index.Rolodex {
+	// create an index config and shadow the document configuration.
+	idxConfig := index.CreateClosedAPIIndexConfig()
+	idxConfig.SpecInfo = info
+	idxConfig.IgnoreArrayCircularReferences = config.IgnoreArrayCircularReferences
+	idxConfig.MaxResolveDepth = config.MaxResolveDepth
+	idxConfig.IgnorePolymorphicCircularReferences = config.IgnorePolymorphicCircularReferences
+	idxConfig.AvoidCircularReferenceCheck = true
+	idxConfig.BaseURL = config.BaseURL
+	idxConfig.BasePath = config.BasePath
+	// if only the location of the root file is known, relative references are resolved from its directory.
+	if idxConfig.BasePath == "" && config.RootFilePath != "" {
+		idxConfig.BasePath = filepath.Dir(utils.ReplaceWindowsDriveWithLinuxPath(config.RootFilePath))
+	}
+	idxConfig.HTTPClient = config.HTTPClient
+	idxConfig.RemoteCacheDir = config.RemoteCacheDir
+	idxConfig.RemoteRetries = config.RemoteRetries
+	idxConfig.RemoteRetryBackoff = config.RemoteRetryBackoff
+	idxConfig.RemoteRetryMaxWait = config.RemoteRetryMaxWait
+	idxConfig.Context = parent
+	idxConfig.SpecFilePath = config.SpecFilePath
+	if idxConfig.SpecFilePath == "" && config.RootFilePath != "" {
+		idxConfig.SpecFilePath = filepath.Base(config.RootFilePath)
+	}
+	idxConfig.Logger = config.Logger
+	extract := config.ExtractRefsSequentially
+	idxConfig.ExtractRefsSequentially = extract
+	rolodex := index.NewRolodex(idxConfig)
+	rolodex.SetRootNode(info.RootNode)
+
+	// if virtual files are provided, they replace the local and remote file systems.
+	if config.VirtualFS != nil {
+		cwd, _ := filepath.Abs(idxConfig.BasePath)
+		memoryFS := index.NewMemoryFS(config.VirtualFS)
+		idxConfig.AllowFileLookup = true
+		fileFS, err := index.NewLocalFSWithConfig(&index.LocalFSConfig{
+			BaseDirectory: cwd,
+			IndexConfig:   idxConfig,
+			FileFilters:   config.FileFilter,
+			DirFS:         memoryFS,
+		})
+		if err == nil {
+			rolodex.AddLocalFS(cwd, fileFS)
+		}
+		remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
+		remoteFS.RemoteHandlerFunc = memoryFS.RemoteHandler
+		idxConfig.AllowRemoteLookup = true
+		rolodex.AddRemoteFS("virtual", remoteFS)
+	}
+
+	// If basePath is provided, add a local filesystem to the rolodex.
+	if config.VirtualFS == nil && (idxConfig.BasePath != "" || config.AllowFileReferences) {
+		var cwd string
+		cwd, _ = filepath.Abs(idxConfig.BasePath)
+		// if a supplied local filesystem is provided, add it to the rolodex.
+		if config.LocalFS != nil {
+			rolodex.AddLocalFS(cwd, config.LocalFS)
+		} else {
+
+			// create a local filesystem
+			localFSConf := index.LocalFSConfig{
+				BaseDirectory: cwd,
+				IndexConfig:   idxConfig,
+				FileFilters:   config.FileFilter,
+			}
+
+			fileFS, _ := index.NewLocalFSWithConfig(&localFSConf)
+			idxConfig.AllowFileLookup = true
+
+			// add the filesystem to the rolodex
+			rolodex.AddLocalFS(cwd, fileFS)
+		}
+	}
+	// if base url is provided, add a remote filesystem to the rolodex.
+	if config.VirtualFS == nil && (idxConfig.BaseURL != nil || config.AllowRemoteReferences) {
+		u := "default"
+		if config.BaseURL != nil {
+			u = config.BaseURL.String()
+		}
+		idxConfig.AllowRemoteLookup = true
+
+		// if a supplied remote filesystem is provided, add it to the rolodex.
+		if config.RemoteFS != nil {
+			rolodex.AddRemoteFS(u, config.RemoteFS)
+		} else {
+
+			// create a remote filesystem
+			remoteFS, _ := index.NewRemoteFSWithConfig(idxConfig)
+			if config.RemoteURLHandler != nil {
+				remoteFS.RemoteHandlerFunc = config.RemoteURLHandler
+			}
+
+			// add to the rolodex
+			rolodex.AddRemoteFS(u, remoteFS)
+		}
+	}
+	return rolodex
+}
+
+// extractionStage is a named function that extracts part of the document.
+type extractionStage struct {
+	name string
+	run  func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error
+}
+
+var extractionStages = []extractionStage{
+	{"info", extractInfo},
+	{"servers", extractServers},
+	{"tags", extractTags},
+	{"components", extractComponents},
+	{"security", extractSecurity},
+	{"externalDocs", extractExternalDocs},
+	{"paths", extractPaths},
+	{"webhooks", extractWebhooks},
+}
+
+// selectExtractionStages returns the built-in extraction stages for the named sections, in the order they normally
+// run. All stages are returned if no sections are named.
+func selectExtractionStages(sections []string) ([]extractionStage, error) {
+	if len(sections) == 0 {
+		return append([]extractionStage{}, extractionStages...), nil
+	}
+	for _, section := range sections {
+		if !slices.ContainsFunc(extractionStages, func(s extractionStage) bool { return s.name == section }) {
+			return nil, fmt.Errorf("unknown document section '%s', cannot create document", section)
+		}
+	}
+	var stages []extractionStage
+	for _, s := range extractionStages {
+		if slices.Contains(sections, s.name) {
+			stages = append(stages, s)
+		}
+	}
+	return stages, nil
+}
+
+// extraExtractionStage wraps a custom datamodel.ExtractorFunc as an extraction stage.
+func extraExtractionStage(fn datamodel.ExtractorFunc) extractionStage {
+	return extractionStage{
+		name: "extra",
+		run: func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error {
+			return fn(ctx, i, d, idx)
+		},
+	}
+}
+
+// progressReporter calls a datamodel.ProgressFunc as each stage completes. It is safe to use from multiple
+// goroutines, calls to the ProgressFunc are serialized.
+type progressReporter struct {
+	mu    sync.Mutex
+	fn    datamodel.ProgressFunc
+	done  int
+	total int
+}
+
+func (p *progressReporter) complete(stage string) {
+	if p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.fn(stage, p.done, p.total)
+}
+
+// ignoredCircularWarnings creates a warning (an *index.CircularReferenceError) for each ignored circular reference,
+// sorted by location so the order is stable.
+func ignoredCircularWarnings(results []*index.CircularReferenceResult) []error {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].LoopPoint.FullDefinition < results[j].LoopPoint.FullDefinition
+	})
+	var warnings []error
+	for _, c := range results {
+		kind := "polymorphic"
+		if c.IsArrayResult {
+			kind = "array"
+		}
+		var line, col int
+		if c.LoopPoint.Node != nil {
+			line, col = c.LoopPoint.Node.Line, c.LoopPoint.Node.Column
+		}
+		warnings = append(warnings, index.NewCircularReferenceError(fmt.Errorf("ignored %s circular reference: %s [%d:%d]",
+			kind, c.GenerateJourneyPath(), line, col), c))
+	}
+	return warnings
+}
+
 func extractInfo(ctx context.Context, info *datamodel.SpecInfo, doc *Document, idx *index.SpecIndex) error {
 	_, ln, vn := utils.FindKeyNodeFullTop(base.InfoLabel, info.RootNode.Content[0].Content)
 	if vn != nil {
@@ -1,17 +1,25 @@
 package v3
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pb33f/libopenapi/index"
 	"github.com/pb33f/libopenapi/utils"
 
 	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/low/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -183,6 +191,148 @@ func TestRolodexRemoteFileSystem_CustomHttpHandler(t *testing.T) {
 	assert.Error(t, err)
 }
 
+type headerTransport struct {
+	calls int
+}
+
+func (h *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.calls++
+	req.Header.Set("X-Pizza", "hot")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// buildHeaderCheckingServer returns a server that only serves requests with the 'X-Pizza' header set, it counts
+// authorized and unauthorized requests.
+func buildHeaderCheckingServer(authorized, unauthorized *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Pizza") != "hot" {
+			*unauthorized++
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		*authorized++
+		_, _ = rw.Write([]byte(`openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object`))
+	}))
+}
+
+func TestRolodexRemoteFileSystem_CustomHttpClient(t *testing.T) {
+	var authorized, unauthorized int
+	server := buildHeaderCheckingServer(&authorized, &unauthorized)
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml#/components/schemas/Pet'`
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	transport := &headerTransport{}
+	cf := datamodel.NewDocumentConfiguration()
+	cf.BaseURL, _ = url.Parse(server.URL)
+	cf.HTTPClient = &http.Client{Transport: transport}
+
+	_, _ = CreateDocumentFromConfig(info, cf)
+	assert.Equal(t, 1, transport.calls)
+	assert.Equal(t, 1, authorized)
+	assert.Zero(t, unauthorized)
+}
+
+func TestRolodexRemoteFileSystem_CustomHttpClient_HandlerWins(t *testing.T) {
+	var authorized, unauthorized int
+	server := buildHeaderCheckingServer(&authorized, &unauthorized)
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml#/components/schemas/Pet'`
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	transport := &headerTransport{}
+	cf := datamodel.NewDocumentConfiguration()
+	cf.BaseURL, _ = url.Parse(server.URL)
+	cf.HTTPClient = &http.Client{Transport: transport}
+
+	var handlerCalls int
+	cf.RemoteURLHandler = func(url string) (*http.Response, error) {
+		handlerCalls++
+		return http.Get(url)
+	}
+
+	_, err := CreateDocumentFromConfig(info, cf)
+	assert.Error(t, err)
+	assert.Equal(t, 1, handlerCalls)
+	assert.Zero(t, transport.calls)
+	assert.Zero(t, authorized)
+	assert.Equal(t, 1, unauthorized)
+}
+
+func TestRolodexRemoteFileSystem_CustomRemoteFS_IgnoresHttpClient(t *testing.T) {
+	var authorized, unauthorized int
+	server := buildHeaderCheckingServer(&authorized, &unauthorized)
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml#/components/schemas/Pet'`
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	remoteFS, _ := index.NewRemoteFSWithRootURL(server.URL)
+	transport := &headerTransport{}
+	cf := datamodel.NewDocumentConfiguration()
+	cf.BaseURL, _ = url.Parse(server.URL)
+	cf.RemoteFS = remoteFS
+	cf.HTTPClient = &http.Client{Transport: transport}
+
+	_, _ = CreateDocumentFromConfig(info, cf)
+	assert.Zero(t, transport.calls)
+	assert.Zero(t, authorized)
+	assert.Equal(t, 1, unauthorized)
+}
+
+func TestRolodexRemoteFileSystem_CustomRemoteFS_NoCache(t *testing.T) {
+	var authorized, unauthorized int
+	server := buildHeaderCheckingServer(&authorized, &unauthorized)
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml#/components/schemas/Pet'`
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	var handlerCalls int
+	remoteFS, _ := index.NewRemoteFSWithRootURL(server.URL)
+	remoteFS.RemoteHandlerFunc = func(url string) (*http.Response, error) {
+		handlerCalls++
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("X-Pizza", "hot")
+		return http.DefaultClient.Do(req)
+	}
+
+	cacheDir := t.TempDir()
+	cf := datamodel.NewDocumentConfiguration()
+	cf.BaseURL, _ = url.Parse(server.URL)
+	cf.RemoteFS = remoteFS
+	cf.RemoteCacheDir = cacheDir
+
+	_, _ = CreateDocumentFromConfig(info, cf)
+	assert.Equal(t, 1, handlerCalls)
+	assert.Equal(t, 1, authorized)
+
+	entries, _ := os.ReadDir(cacheDir)
+	assert.Empty(t, entries)
+}
+
 func TestCircularReference_IgnoreArray(t *testing.T) {
 	spec := `openapi: 3.1.0
 components:
@@ -207,6 +357,37 @@ components:
 	})
 	assert.NotNil(t, circDoc)
 	assert.Len(t, utils.UnwrapErrors(err), 0)
+	assert.Len(t, circDoc.Warnings, 1)
+	assert.Equal(t, "ignored array circular reference: ProductCategory -> ProductCategory [5:7]",
+		circDoc.Warnings[0].Error())
+
+	var circErr *index.CircularReferenceError
+	require.True(t, errors.As(circDoc.Warnings[0], &circErr))
+	assert.Equal(t, []string{"#/components/schemas/ProductCategory", "#/components/schemas/ProductCategory"},
+		circErr.Journey)
+	assert.True(t, circErr.CircularReference.IsArrayResult)
+}
+
+func TestCircularReference_NotIgnored_NoWarnings(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    ProductCategory:
+      type: "object"
+      properties:
+        children:
+          type: "array"
+          minItems: 1
+          items:
+            $ref: "#/components/schemas/ProductCategory"
+      required:
+        - "children"`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	circDoc, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NotNil(t, circDoc)
+	assert.Len(t, utils.UnwrapErrors(err), 1)
+	assert.Empty(t, circDoc.Warnings)
 }
 
 func TestCircularReference_IgnorePoly(t *testing.T) {
@@ -233,6 +414,8 @@ components:
 	})
 	assert.NotNil(t, circDoc)
 	assert.Len(t, utils.UnwrapErrors(err), 0)
+	assert.Len(t, circDoc.Warnings, 1)
+	assert.Contains(t, circDoc.Warnings[0].Error(), "ignored polymorphic circular reference")
 }
 
 func BenchmarkCreateDocument_Stripe(b *testing.B) {
@@ -567,6 +750,225 @@ func TestCreateDocument_Components_Schemas(t *testing.T) {
 		p.Value.Schema().Description.Value)
 }
 
+func TestCreateDocument_ProgressFunc(t *testing.T) {
+	data, _ := os.ReadFile("../../../test_specs/burgershop.openapi.yaml")
+	info, _ := datamodel.ExtractSpecInfo(data)
+
+	var stages []string
+	var last, total int
+	config := datamodel.NewDocumentConfiguration()
+	config.ProgressFunc = func(stage string, done, tot int) {
+		stages = append(stages, stage)
+		assert.Equal(t, last+1, done)
+		last, total = done, tot
+	}
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+
+	assert.Equal(t, []string{
+		"index", "circular references", "info", "servers", "tags", "components",
+		"security", "externalDocs", "paths", "webhooks",
+	}, stages)
+	assert.Equal(t, 10, total)
+	assert.Equal(t, total, last)
+}
+
+func TestCreateDocument_DetectDuplicateKeys(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: dupes
+components:
+  schemas:
+    Pet:
+      type: object
+components:
+  schemas:
+    Owner:
+      type: object`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	// disabled by default.
+	d, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	assert.Empty(t, d.Warnings)
+
+	config := datamodel.NewDocumentConfiguration()
+	config.DetectDuplicateKeys = true
+	d, err = CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	require.Len(t, d.Warnings, 1)
+	assert.Equal(t, "duplicate key 'components' found at '/components' [4:1, 8:1]", d.Warnings[0].Error())
+
+	var dupe *utils.DuplicateKey
+	config.DuplicateKeysAreErrors = true
+	d, err = CreateDocumentFromConfig(info, config)
+	assert.NotNil(t, d)
+	assert.Empty(t, d.Warnings)
+	require.True(t, errors.As(err, &dupe))
+	assert.Equal(t, []int{4, 8}, dupe.Lines)
+}
+
+func TestCreateDocument_StrictVersionCheck(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: mismatch
+paths: {}
+webhooks:
+  newPet:
+    post:
+      description: new pet
+components:
+  schemas:
+    Pet:
+      type: [object, 'null']`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	// disabled by default.
+	d, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	assert.Empty(t, d.Warnings)
+
+	config := datamodel.NewDocumentConfiguration()
+	config.StrictVersionCheck = true
+	d, err = CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	require.Len(t, d.Warnings, 2)
+	assert.Equal(t, "'webhooks' found at '/webhooks' is not supported by OpenAPI 3.0.3, "+
+		"it was added in OpenAPI 3.1 [5:1]", d.Warnings[0].Error())
+
+	var mismatch *VersionMismatch
+	require.True(t, errors.As(d.Warnings[1], &mismatch))
+	assert.Equal(t, "/components/schemas/Pet/type", mismatch.Path)
+}
+
+func TestCreatePartialDocument(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: partial
+servers:
+  - url: https://pb33f.io
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+x-team: pets`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	var stages []string
+	config := datamodel.NewDocumentConfiguration()
+	config.ProgressFunc = func(stage string, done, total int) {
+		stages = append(stages, stage)
+	}
+	d, err := CreatePartialDocument(info, config, "paths")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"index", "circular references", "paths"}, stages)
+	assert.Equal(t, "3.1.0", d.Version.Value)
+	assert.Equal(t, 1, d.Extensions.Len())
+	assert.True(t, d.Info.IsEmpty())
+	assert.True(t, d.Servers.IsEmpty())
+	assert.True(t, d.Components.IsEmpty())
+
+	// references into sections that were not extracted still resolve.
+	op := d.Paths.Value.FindPath("/pets").Value.Get.Value
+	mt := op.Responses.Value.FindResponseByCode("200").Value.FindContent("application/json").Value
+	assert.Equal(t, "object", mt.Schema.Value.Schema().Type.Value.A)
+
+	// no sections means the whole document.
+	d, err = CreatePartialDocument(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	assert.Equal(t, "partial", d.Info.Value.Title.Value)
+	assert.Equal(t, 1, d.Components.Value.Schemas.Value.Len())
+
+	d, err = CreatePartialDocument(info, datamodel.NewDocumentConfiguration(), "info", "pathz")
+	assert.Nil(t, d)
+	assert.EqualError(t, err, "unknown document section 'pathz', cannot create document")
+}
+
+type ctxTestKey string
+
+func TestCreateDocumentFromConfigWithContext_Values(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: context
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+	ctx := context.WithValue(context.Background(), ctxTestKey("logger"), "request-scoped")
+	d, err := CreateDocumentFromConfigWithContext(ctx, info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	assert.Equal(t, "context", d.Info.Value.Title.Value)
+
+	pet := d.Components.Value.FindSchema("Pet").Value
+	assert.Equal(t, "request-scoped", pet.GetContext().Value(ctxTestKey("logger")))
+}
+
+func TestCreateDocumentFromConfigWithContext_Cancelled(t *testing.T) {
+	info, _ := datamodel.ExtractSpecInfo([]byte("openapi: 3.0.3\ninfo:\n  title: cancelled"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d, err := CreateDocumentFromConfigWithContext(ctx, info, datamodel.NewDocumentConfiguration())
+	assert.Nil(t, d)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestCreateDocumentFromConfigWithContext_RemoteDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// a host that never answers, until the request is cancelled.
+		select {
+		case <-req.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+
+	spec := fmt.Sprintf(`openapi: 3.1.0
+info:
+  title: slow
+components:
+  schemas:
+    Pet:
+      $ref: '%s/pet.yaml'`, server.URL)
+	info, _ := datamodel.ExtractSpecInfo([]byte(spec))
+
+	config := datamodel.NewDocumentConfiguration()
+	config.AllowRemoteReferences = true
+	config.RemoteRetries = 3
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	d, err := CreateDocumentFromConfigWithContext(ctx, info, config)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.NotNil(t, d)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
 func TestCreateDocument_Components_SecuritySchemes(t *testing.T) {
 	initTest()
 	components := doc.Components.Value
@@ -742,6 +1144,46 @@ webhooks:
 		err.Error())
 }
 
+func TestCreateDocument_Webhooks_RefToComponentPathItem(t *testing.T) {
+	yml := `openapi: 3.1.0
+webhooks:
+  newPet:
+    $ref: '#/components/pathItems/NewPet'
+components:
+  pathItems:
+    NewPet:
+      post:
+        description: a new pet has arrived`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	d, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, orderedmap.Len(d.Webhooks.Value))
+
+	hook := d.Webhooks.Value.First().Value()
+	assert.True(t, hook.IsReference())
+	assert.Equal(t, "#/components/pathItems/NewPet", hook.GetReference())
+	assert.Equal(t, "a new pet has arrived", hook.Value.Post.Value.Description.Value)
+}
+
+func TestCreateDocument_Webhooks_RefToComponentPathItem_Dangling(t *testing.T) {
+	yml := `openapi: 3.1.0
+webhooks:
+  newPet:
+    $ref: '#/components/pathItems/Missing'
+components:
+  pathItems:
+    NewPet:
+      post:
+        description: a new pet has arrived`
+
+	info, _ := datamodel.ExtractSpecInfo([]byte(yml))
+	_, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"flat map build failed: reference cannot be found: reference '#/components/pathItems/Missing' at line 4, column 5 was not found")
+}
+
 func TestCreateDocument_Components_Error_Extract(t *testing.T) {
 	yml := `openapi: 3.0
 components:
@@ -881,3 +1323,271 @@ func ExampleCreateDocument() {
 	fmt.Print(document.Info.Value.Contact.Value.Email.Value)
 	// Output: apiteam@swagger.io
 }
+
+func TestCreateDocument_ExtraExtractors(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: extractors
+x-internal-routing:
+  cluster: east
+  weight: 10`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	type routing struct {
+		Cluster string `yaml:"cluster"`
+		Weight  int    `yaml:"weight"`
+	}
+	var parsed routing
+	var stages []string
+	config := datamodel.NewDocumentConfiguration()
+	config.ProgressFunc = func(stage string, done, total int) {
+		stages = append(stages, stage)
+	}
+	config.ExtraExtractors = []datamodel.ExtractorFunc{
+		func(ctx context.Context, info *datamodel.SpecInfo, document any, idx any) error {
+			doc := document.(*Document)
+			assert.Equal(t, doc.Index, idx.(*index.SpecIndex))
+			assert.Equal(t, "extractors", doc.Info.Value.Title.Value)
+			_, _, vn := utils.FindKeyNodeFullTop("x-internal-routing", info.RootNode.Content[0].Content)
+			require.NotNil(t, vn)
+			return vn.Decode(&parsed)
+		},
+		func(ctx context.Context, info *datamodel.SpecInfo, document any, idx any) error {
+			return errors.New("routing weight is too low")
+		},
+	}
+
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NotNil(t, d)
+	assert.EqualError(t, err, "routing weight is too low")
+	assert.Equal(t, routing{Cluster: "east", Weight: 10}, parsed)
+	assert.Equal(t, []string{"extra", "extra"}, stages[len(stages)-2:])
+	assert.Len(t, stages, 12)
+}
+
+func TestCreateDocument_VirtualFS(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: virtual
+components:
+  schemas:
+    Pet:
+      $ref: 'schemas/pet.yaml'
+    Owner:
+      $ref: 'https://pb33f.io/owner.yaml#/Owner'`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	config := datamodel.NewDocumentConfiguration()
+	config.VirtualFS = map[string][]byte{
+		"schemas/pet.yaml": []byte(`type: object
+properties:
+  tag:
+    $ref: 'tag.yaml'`),
+		"schemas/tag.yaml": []byte(`type: string`),
+		"https://pb33f.io/owner.yaml": []byte(`Owner:
+  type: object
+  description: remote owner`),
+	}
+
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+
+	pet := d.Components.Value.FindSchema("Pet").Value.Schema()
+	require.NotNil(t, pet)
+	assert.Equal(t, "object", pet.Type.Value.A)
+	tag := pet.FindProperty("tag").Value.Schema()
+	require.NotNil(t, tag)
+	assert.Equal(t, "string", tag.Type.Value.A)
+
+	owner := d.Components.Value.FindSchema("Owner").Value.Schema()
+	require.NotNil(t, owner)
+	assert.Equal(t, "remote owner", owner.Description.Value)
+
+	// missing files are reported the same way as missing files on disk.
+	config.VirtualFS = map[string][]byte{}
+	info, _ = datamodel.ExtractSpecInfo([]byte(spec))
+	_, err = CreateDocumentFromConfig(info, config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot resolve reference `schemas/pet.yaml`")
+}
+
+func TestCreateDocument_ErrorsInStageOrder(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: ordered
+paths:
+  /pets:
+    $ref: '#/paths/~1missing'`
+
+	extractor := func(msg string, delay time.Duration) datamodel.ExtractorFunc {
+		return func(ctx context.Context, info *datamodel.SpecInfo, document any, idx any) error {
+			time.Sleep(delay)
+			return errors.New(msg)
+		}
+	}
+
+	var first string
+	for i := 0; i < 5; i++ {
+		info, err := datamodel.ExtractSpecInfo([]byte(spec))
+		require.NoError(t, err)
+		config := datamodel.NewDocumentConfiguration()
+		config.ExtraExtractors = []datamodel.ExtractorFunc{
+			extractor("first", 2*time.Millisecond),
+			extractor("second", 0),
+			extractor("third", time.Millisecond),
+		}
+		_, err = CreateDocumentFromConfig(info, config)
+		require.Error(t, err)
+
+		msgs := strings.Split(err.Error(), "\n")
+		assert.Equal(t, []string{"first", "second", "third"}, msgs[len(msgs)-3:])
+		if i == 0 {
+			first = err.Error()
+			continue
+		}
+		assert.Equal(t, first, err.Error())
+	}
+}
+
+func TestCreateDocument_RootFilePath(t *testing.T) {
+	dir := t.TempDir()
+	spec := []byte(`openapi: 3.1.0
+info:
+  title: root file
+components:
+  schemas:
+    Pet:
+      $ref: './common.yaml'`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), spec, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.yaml"), []byte(`type: object
+description: common pet`), 0o644))
+
+	// without a base path or root file path, the reference cannot be resolved.
+	info, _ := datamodel.ExtractSpecInfo(spec)
+	_, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	assert.Error(t, err)
+
+	info, _ = datamodel.ExtractSpecInfo(spec)
+	config := datamodel.NewDocumentConfiguration()
+	config.RootFilePath = filepath.Join(dir, "openapi.yaml")
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	pet := d.Components.Value.FindSchema("Pet").Value.Schema()
+	require.NotNil(t, pet)
+	assert.Equal(t, "common pet", pet.Description.Value)
+
+	// an explicit base path wins.
+	info, _ = datamodel.ExtractSpecInfo(spec)
+	config.BasePath = t.TempDir()
+	_, err = CreateDocumentFromConfig(info, config)
+	assert.Error(t, err)
+}
+
+func TestCreateDocument_LocalAndRemoteReferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/owner.yaml":
+			_, _ = w.Write([]byte(`type: object
+description: remote owner
+properties:
+  address:
+    $ref: 'address.yaml'`))
+		case "/schemas/address.yaml":
+			_, _ = w.Write([]byte(`type: object
+description: remote address`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	spec := []byte(`openapi: 3.1.0
+info:
+  title: hybrid
+components:
+  schemas:
+    Pet:
+      $ref: 'http-pet.yaml'
+    Owner:
+      $ref: '` + server.URL + `/schemas/owner.yaml'`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openapi.yaml"), spec, 0o644))
+	// a local file whose name starts with 'http' must not be mistaken for a URL.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "http-pet.yaml"), []byte(`type: object
+description: local pet`), 0o644))
+
+	info, _ := datamodel.ExtractSpecInfo(spec)
+	config := datamodel.NewDocumentConfiguration()
+	config.BasePath = dir
+	config.SpecFilePath = "openapi.yaml"
+	config.AllowRemoteReferences = true
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+
+	pet := d.Components.Value.FindSchema("Pet").Value.Schema()
+	require.NotNil(t, pet)
+	assert.Equal(t, "local pet", pet.Description.Value)
+
+	owner := d.Components.Value.FindSchema("Owner").Value.Schema()
+	require.NotNil(t, owner)
+	assert.Equal(t, "remote owner", owner.Description.Value)
+
+	// relative references in a remote file are resolved against that file's URL.
+	address := owner.FindProperty("address").Value.Schema()
+	require.NotNil(t, address)
+	assert.Equal(t, "remote address", address.Description.Value)
+}
+
+func TestCreateDocument_SchemaDialectValidator(t *testing.T) {
+	spec := `openapi: 3.1.0
+jsonSchemaDialect: https://example.com/dialect
+info:
+  title: dialects
+components:
+  schemas:
+    Pet:
+      type: object
+    Toy:
+      $schema: https://json-schema.org/draft-04/schema
+      type: object`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+
+	var dialects []string
+	config := datamodel.NewDocumentConfiguration()
+	config.SchemaDialectValidator = func(dialect string, schema any) error {
+		dialects = append(dialects, dialect)
+		s := schema.(*base.SchemaProxy).Schema()
+		if s.SchemaTypeRef.Value != "" {
+			return fmt.Errorf("unsupported $schema '%s'", s.SchemaTypeRef.Value)
+		}
+		return nil
+	}
+	d, err := CreateDocumentFromConfig(info, config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/dialect", "https://example.com/dialect"}, dialects)
+	require.Len(t, d.Warnings, 1)
+	assert.Equal(t, "schema 'Toy' [9:5] is not valid for dialect 'https://example.com/dialect': "+
+		"unsupported $schema 'https://json-schema.org/draft-04/schema'", d.Warnings[0].Error())
+
+	config.SchemaDialectErrors = true
+	d, err = CreateDocumentFromConfig(info, config)
+	assert.ErrorContains(t, err, "unsupported $schema")
+	assert.Empty(t, d.Warnings)
+}
+
+func TestCreateDocument_BOMAndCRLF(t *testing.T) {
+	spec := "\xef\xbb\xbfopenapi: 3.1.0\r\ninfo:\r\n  title: windows\r\npaths: {}\r\n"
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	d, err := CreateDocumentFromConfig(info, datamodel.NewDocumentConfiguration())
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", d.Version.Value)
+	assert.Equal(t, "windows", d.Info.Value.Title.Value)
+}
@@ -87,6 +87,13 @@ type Document struct {
 	// Rolodex is a reference to the rolodex used when creating this document.
 	Rolodex *index.Rolodex
 
+	// Warnings contains non-fatal problems found when creating the document, for example circular references that
+	// were ignored because IgnoreArrayCircularReferences or IgnorePolymorphicCircularReferences is set.
+	// Warnings are not included in the error returned when creating the document.
+	//
+	// This property is not a part of the OpenAPI schema, this is custom to libopenapi.
+	Warnings []error
+
 	low.NodeMap
 }
 
@@ -103,6 +110,14 @@ func (d *Document) FindSecurityRequirement(name string) []low.ValueReference[str
 	return nil
 }
 
+// GetRootNode returns the root node of the document, or nil if the document has not been indexed.
+func (d *Document) GetRootNode() *yaml.Node {
+	if d.Index == nil {
+		return nil
+	}
+	return d.Index.GetRootNode()
+}
+
 // GetExtensions returns all Document extensions and satisfies the low.HasExtensions interface.
 func (d *Document) GetExtensions() *orderedmap.Map[low.KeyReference[string], low.ValueReference[*yaml.Node]] {
 	return d.Extensions
@@ -0,0 +1,43 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/index"
+)
+
+// schemaDialectStage wraps the components extraction stage, so every component schema is passed to validate once
+// it has been extracted. Validation errors are returned if asErrors is set, otherwise they are added to the
+// document warnings.
+func schemaDialectStage(stage extractionStage, validate datamodel.SchemaDialectValidatorFunc, asErrors bool) extractionStage {
+	return extractionStage{
+		name: stage.name,
+		run: func(ctx context.Context, i *datamodel.SpecInfo, d *Document, idx *index.SpecIndex) error {
+			if err := stage.run(ctx, i, d, idx); err != nil {
+				return err
+			}
+			if d.Components.Value == nil {
+				return nil
+			}
+			dialect := d.JsonSchemaDialect.Value
+			var errs []error
+			for k, v := range d.Components.Value.Schemas.Value.FromOldest() {
+				if err := validate(dialect, v.Value); err != nil {
+					errs = append(errs, fmt.Errorf("schema '%s' [%d:%d] is not valid for dialect '%s': %w",
+						k.Value, k.KeyNode.Line, k.KeyNode.Column, dialect, err))
+				}
+			}
+			if asErrors {
+				return errors.Join(errs...)
+			}
+			d.Warnings = append(d.Warnings, errs...)
+			return nil
+		},
+	}
+}
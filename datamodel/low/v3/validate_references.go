@@ -0,0 +1,23 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"context"
+
+	"github.com/pb33f/libopenapi/datamodel"
+)
+
+// ValidateReferences checks that every reference in a specification can be resolved, without building the
+// document model. The rolodex is set up and indexed the same way as CreateDocumentFromConfig (honoring the base
+// path, base URL and circular reference settings of the configuration), and only reference resolution and circular
+// reference errors are returned. No errors means every reference resolved.
+func ValidateReferences(info *datamodel.SpecInfo, config *datamodel.DocumentConfiguration) []error {
+	rolodex := newRolodex(context.Background(), info, config)
+	_ = rolodex.IndexTheRolodex()
+	if !config.SkipCircularReferenceCheck {
+		rolodex.CheckForCircularReferences()
+	}
+	return rolodex.GetCaughtErrors()
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReferences(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	assert.Empty(t, ValidateReferences(info, datamodel.NewDocumentConfiguration()))
+}
+
+func TestValidateReferences_Missing(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	errs := ValidateReferences(info, datamodel.NewDocumentConfiguration())
+	require.NotEmpty(t, errs)
+	for _, e := range errs {
+		assert.Contains(t, e.Error(), "#/components/schemas/Owner")
+	}
+}
+
+func TestValidateReferences_Circular(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [owner]
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      type: object
+      required: [pets]
+      properties:
+        pets:
+          type: array
+          minItems: 1
+          items:
+            $ref: '#/components/schemas/Pet'`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	errs := ValidateReferences(info, datamodel.NewDocumentConfiguration())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "infinite circular reference")
+
+	config := datamodel.NewDocumentConfiguration()
+	config.IgnoreArrayCircularReferences = true
+	assert.Empty(t, ValidateReferences(info, config))
+
+	config = datamodel.NewDocumentConfiguration()
+	config.SkipCircularReferenceCheck = true
+	assert.Empty(t, ValidateReferences(info, config))
+}
+
+func TestValidateReferences_BasePath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pet.yaml"), []byte("type: object"), 0o644))
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      $ref: 'pet.yaml'
+    Toy:
+      $ref: 'toy.yaml'`
+
+	info, err := datamodel.ExtractSpecInfo([]byte(spec))
+	require.NoError(t, err)
+	config := datamodel.NewDocumentConfiguration()
+	config.BasePath = dir
+	errs := ValidateReferences(info, config)
+	require.NotEmpty(t, errs)
+	for _, e := range errs {
+		assert.Contains(t, e.Error(), "toy.yaml")
+		assert.NotContains(t, e.Error(), "pet.yaml")
+	}
+}
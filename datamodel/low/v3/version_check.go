@@ -0,0 +1,188 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// VersionMismatch represents a construct in a document that is not supported by the OpenAPI version the document
+// declares, for example 'webhooks' in an OpenAPI 3.0 document, or 'nullable' in an OpenAPI 3.1 document.
+type VersionMismatch struct {
+	Construct string // the unsupported construct, for example 'webhooks'.
+	Path      string // a JSON pointer to the construct, for example '/components/schemas/Pet/nullable'.
+	Version   string // the declared OpenAPI version.
+	Reason    string // why the construct is not supported.
+	Line      int
+	Column    int
+}
+
+// Error returns a description of the mismatch, with the line and column of the construct.
+func (v *VersionMismatch) Error() string {
+	return fmt.Sprintf("'%s' found at '%s' is not supported by OpenAPI %s, %s [%d:%d]",
+		v.Construct, v.Path, v.Version, v.Reason, v.Line, v.Column)
+}
+
+const (
+	only31 = "it was added in OpenAPI 3.1"
+	only30 = "it was removed in OpenAPI 3.1"
+)
+
+// schema keywords that only exist in the JSON Schema dialect used by OpenAPI 3.1.
+var schemaKeywords31 = []string{
+	"const", "prefixItems", "if", "then", "else", "dependentSchemas", "dependentRequired",
+	"unevaluatedItems", "unevaluatedProperties", "contains", "minContains", "maxContains", "propertyNames",
+	"$defs", "$schema", "$id", "$anchor", "$dynamicRef", "$dynamicAnchor", "contentEncoding",
+	"contentMediaType", "contentSchema", "examples",
+}
+
+// schema keywords that hold a single schema.
+var schemaChildKeywords = []string{
+	"items", "not", "additionalProperties", "if", "then", "else", "contains", "propertyNames",
+	"unevaluatedItems", "unevaluatedProperties", "contentSchema",
+}
+
+// schema keywords that hold a list of schemas.
+var schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+
+// schema keywords that hold a map of schemas.
+var schemaMapKeywords = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+
+// versionChecker walks a document, collecting constructs not supported by the declared version.
+type versionChecker struct {
+	version    string
+	is30, is31 bool
+	found      []error
+}
+
+// checkVersionConstructs returns a *VersionMismatch for every construct in the document that is not supported by
+// the declared OpenAPI version. Only 3.0 and 3.1 documents are checked.
+func checkVersionConstructs(version string, root *yaml.Node) []error {
+	v := &versionChecker{
+		version: version,
+		is30:    strings.HasPrefix(version, "3.0"),
+		is31:    strings.HasPrefix(version, "3.1"),
+	}
+	if (!v.is30 && !v.is31) || root == nil {
+		return nil
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if v.is30 {
+		v.keyword(root, "", "webhooks", only31)
+		v.keyword(root, "", JSONSchemaDialectLabel, only31)
+		v.keyword(child(root, "info"), "/info", "summary", only31)
+		v.keyword(child(child(root, "info"), "license"), "/info/license", "identifier", only31)
+		v.keyword(child(root, ComponentsLabel), "/"+ComponentsLabel, "pathItems", only31)
+	}
+	v.walk(root, "")
+	return v.found
+}
+
+func (v *versionChecker) add(key *yaml.Node, path, reason string) {
+	v.found = append(v.found, &VersionMismatch{
+		Construct: key.Value,
+		Path:      path + "/" + utils.EscapeJSONPointer(key.Value),
+		Version:   v.version,
+		Reason:    reason,
+		Line:      key.Line,
+		Column:    key.Column,
+	})
+}
+
+// keyword reports the named key of a mapping node, if it exists.
+func (v *versionChecker) keyword(node *yaml.Node, path, name, reason string) {
+	if node == nil {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			v.add(node.Content[i], path, reason)
+		}
+	}
+}
+
+// walk looks for schemas anywhere in the document, skipping example values and extensions.
+func (v *versionChecker) walk(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			v.walk(n, path+"/"+strconv.Itoa(i))
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, val := node.Content[i], node.Content[i+1]
+			p := path + "/" + utils.EscapeJSONPointer(k.Value)
+			switch {
+			case k.Value == "example" || k.Value == "examples" || strings.HasPrefix(k.Value, "x-"):
+				continue
+			case k.Value == "schema":
+				v.schema(val, p)
+			case k.Value == "schemas" && path == "/"+ComponentsLabel && val.Kind == yaml.MappingNode:
+				for j := 0; j+1 < len(val.Content); j += 2 {
+					v.schema(val.Content[j+1], p+"/"+utils.EscapeJSONPointer(val.Content[j].Value))
+				}
+			default:
+				v.walk(val, p)
+			}
+		}
+	}
+}
+
+// schema checks the keywords of a schema, and every schema inside it.
+func (v *versionChecker) schema(node *yaml.Node, path string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, val := node.Content[i], node.Content[i+1]
+		switch {
+		case v.is31 && k.Value == "nullable":
+			v.add(k, path, only30+", use a 'null' type instead")
+		case v.is31 && (k.Value == "exclusiveMinimum" || k.Value == "exclusiveMaximum") && val.Tag == "!!bool":
+			v.add(k, path, "it must be a number in OpenAPI 3.1")
+		case v.is30 && (k.Value == "exclusiveMinimum" || k.Value == "exclusiveMaximum") && val.Tag != "!!bool":
+			v.add(k, path, "it must be a boolean in OpenAPI 3.0")
+		case v.is30 && k.Value == "type" && val.Kind == yaml.SequenceNode:
+			v.add(k, path, "multiple types were added in OpenAPI 3.1")
+		case v.is30 && slices.Contains(schemaKeywords31, k.Value):
+			v.add(k, path, only31)
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, val := node.Content[i], node.Content[i+1]
+		p := path + "/" + utils.EscapeJSONPointer(k.Value)
+		switch {
+		case slices.Contains(schemaChildKeywords, k.Value):
+			v.schema(val, p)
+		case slices.Contains(schemaListKeywords, k.Value) && val.Kind == yaml.SequenceNode:
+			for j, n := range val.Content {
+				v.schema(n, p+"/"+strconv.Itoa(j))
+			}
+		case slices.Contains(schemaMapKeywords, k.Value) && val.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(val.Content); j += 2 {
+				v.schema(val.Content[j+1], p+"/"+utils.EscapeJSONPointer(val.Content[j].Value))
+			}
+		}
+	}
+}
+
+func child(node *yaml.Node, name string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func versionCheckPaths(t *testing.T, version, spec string) []string {
+	var root yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(spec), &root))
+	var paths []string
+	for _, e := range checkVersionConstructs(version, &root) {
+		paths = append(paths, e.(*VersionMismatch).Path)
+	}
+	return paths
+}
+
+func TestCheckVersionConstructs_30(t *testing.T) {
+	spec := `jsonSchemaDialect: https://spec.openapis.org/oas/3.1/dialect/base
+info:
+  summary: pets
+  license:
+    identifier: MIT
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            exclusiveMinimum: 0
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                prefixItems:
+                  - const: pet
+              examples:
+                pets:
+                  value:
+                    const: not a schema
+components:
+  pathItems: {}
+  schemas:
+    Pet:
+      properties:
+        name:
+          type: string
+          nullable: true
+        tags:
+          items:
+            $defs: {}
+    Old:
+      exclusiveMaximum: true`
+
+	assert.Equal(t, []string{
+		"/jsonSchemaDialect",
+		"/info/summary",
+		"/info/license/identifier",
+		"/components/pathItems",
+		"/paths/~1pets/get/parameters/0/schema/exclusiveMinimum",
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/prefixItems",
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/prefixItems/0/const",
+		"/components/schemas/Pet/properties/tags/items/$defs",
+	}, versionCheckPaths(t, "3.0.3", spec))
+}
+
+func TestCheckVersionConstructs_31(t *testing.T) {
+	spec := `webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - type: string
+                  nullable: true
+components:
+  schemas:
+    Pet:
+      type: [object, 'null']
+      const: pet
+      exclusiveMinimum: true
+      exclusiveMaximum: 10`
+
+	assert.Equal(t, []string{
+		"/webhooks/newPet/post/requestBody/content/application~1json/schema/allOf/0/nullable",
+		"/components/schemas/Pet/exclusiveMinimum",
+	}, versionCheckPaths(t, "3.1.0", spec))
+}
+
+func TestCheckVersionConstructs_OtherVersions(t *testing.T) {
+	assert.Nil(t, checkVersionConstructs("2.0", &yaml.Node{}))
+	assert.Nil(t, checkVersionConstructs("3.1.0", nil))
+}
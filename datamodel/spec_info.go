@@ -4,6 +4,7 @@
 package datamodel
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,7 +28,7 @@ type SpecInfo struct {
 	VersionNumeric      float32                 `json:"versionNumeric"`
 	SpecFormat          string                  `json:"format"`
 	SpecFileType        string                  `json:"fileType"`
-	SpecBytes           *[]byte                 `json:"bytes"` // the original byte array
+	SpecBytes           *[]byte                 `json:"bytes"` // the original byte array, without a BOM and with LF line endings
 	RootNode            *yaml.Node              `json:"-"`     // reference to the root node of the spec.
 	SpecJSONBytes       *[]byte                 `json:"-"`     // original bytes converted to JSON
 	SpecJSON            *map[string]interface{} `json:"-"`     // standard JSON map of original bytes
@@ -60,6 +61,9 @@ func ExtractSpecInfoWithDocumentCheck(spec []byte, bypass bool) (*SpecInfo, erro
 
 	specInfo := &SpecInfo{}
 
+	// specs authored on Windows often have a BOM and CRLF line endings, which can hide the version key.
+	spec = normalizeSpecBytes(spec)
+
 	// set original bytes
 	specInfo.SpecBytes = &spec
 
@@ -201,6 +205,16 @@ func ExtractSpecInfo(spec []byte) (*SpecInfo, error) {
 	return ExtractSpecInfoWithDocumentCheck(spec, false)
 }
 
+// normalizeSpecBytes strips a leading UTF-8 byte order mark, and converts CRLF line endings to LF. The spec is
+// returned untouched if there is nothing to change.
+func normalizeSpecBytes(spec []byte) []byte {
+	spec = bytes.TrimPrefix(spec, []byte("\xef\xbb\xbf"))
+	if bytes.Contains(spec, []byte("\r\n")) {
+		spec = bytes.ReplaceAll(spec, []byte("\r\n"), []byte("\n"))
+	}
+	return spec
+}
+
 // extract version number from specification
 func parseVersionTypeData(d interface{}) (string, int, error) {
 	r := []rune(strings.TrimSpace(fmt.Sprintf("%v", d)))
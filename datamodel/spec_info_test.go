@@ -304,3 +304,37 @@ func TestExtractSpecInfoSync_Error(t *testing.T) {
 	_, e := ExtractSpecInfoWithDocumentCheckSync([]byte(random), true)
 	assert.Error(t, e)
 }
+
+func TestExtractSpecInfo_BOM(t *testing.T) {
+	spec := "\xef\xbb\xbfopenapi: 3.1.0\ninfo:\n  title: bom\n"
+	r, e := ExtractSpecInfo([]byte(spec))
+	assert.NoError(t, e)
+	assert.Equal(t, "3.1.0", r.Version)
+	assert.Equal(t, YAMLFileType, r.SpecFileType)
+	assert.Equal(t, "openapi: 3.1.0\ninfo:\n  title: bom\n", string(*r.SpecBytes))
+}
+
+func TestExtractSpecInfo_BOM_JSON(t *testing.T) {
+	spec := "\xef\xbb\xbf{\"openapi\": \"3.0.1\", \"info\": {\"title\": \"bom\"}}"
+	r, e := ExtractSpecInfo([]byte(spec))
+	assert.NoError(t, e)
+	assert.Equal(t, "3.0.1", r.Version)
+	assert.Equal(t, JSONFileType, r.SpecFileType)
+}
+
+func TestExtractSpecInfo_CRLF(t *testing.T) {
+	spec := "\xef\xbb\xbfopenapi: 3.1.0\r\ninfo:\r\n  title: crlf\r\n  description: |\r\n    two\r\n    lines\r\n"
+	r, e := ExtractSpecInfo([]byte(spec))
+	assert.NoError(t, e)
+	assert.Equal(t, "3.1.0", r.Version)
+	assert.Equal(t, 7, r.NumLines)
+	assert.NotContains(t, string(*r.SpecBytes), "\r")
+
+	var doc struct {
+		Info struct {
+			Description string `yaml:"description"`
+		} `yaml:"info"`
+	}
+	assert.NoError(t, r.RootNode.Decode(&doc))
+	assert.Equal(t, "two\nlines\n", doc.Info.Description)
+}
@@ -3,19 +3,23 @@ package datamodel
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"sync"
 
 	"github.com/pb33f/libopenapi/orderedmap"
 )
 
 type (
-	ActionFunc[T any]                   func(T) error
-	TranslateFunc[IN any, OUT any]      func(IN) (OUT, error)
-	TranslateSliceFunc[IN any, OUT any] func(int, IN) (OUT, error)
-	TranslateMapFunc[IN any, OUT any]   func(IN) (OUT, error)
-	ResultFunc[V any]                   func(V) error
+	ActionFunc[T any]                          func(T) error
+	TranslateFunc[IN any, OUT any]             func(IN) (OUT, error)
+	TranslateSliceFunc[IN any, OUT any]        func(int, IN) (OUT, error)
+	TranslateMapFunc[IN any, OUT any]          func(IN) (OUT, error)
+	ResultFunc[V any]                          func(V) error
+	TranslateKeyValueFunc[K any, V any, R any] func(K, V) (R, error)
+	KeyResultFunc[K any, R any]                func(K, R) error
 )
 
 type continueError struct {
@@ -24,12 +28,62 @@ type continueError struct {
 
 var Continue = &continueError{error: errors.New("Continue")}
 
+// SkipError works the same way as Continue, the item is skipped and iteration continues, but it also carries the
+// reason the item was skipped. Create one using Skip.
+type SkipError struct {
+	Reason string
+}
+
+// Error returns the reason the item was skipped.
+func (s *SkipError) Error() string {
+	return "skipped: " + s.Reason
+}
+
+// Skip returns a *SkipError, that can be returned by translate() to skip an item, with a reason.
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+// SkippedItem is an item that was skipped by translate(), with the index of the item and the reason it was skipped.
+type SkippedItem struct {
+	Index  int
+	Reason string
+}
+
+// isContinue returns true if the error is Continue or a *SkipError.
+func isContinue(err error) bool {
+	if err == Continue {
+		return true
+	}
+	var skip *SkipError
+	return err != nil && errors.As(err, &skip)
+}
+
 type jobStatus[OUT any] struct {
 	done   chan struct{}
 	cont   bool
+	idx    int
+	err    error
 	result OUT
 }
 
+// TranslateError is returned by TranslateSliceParallelPartial when translate() fails, Index is the index of the
+// item that failed.
+type TranslateError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the index of the item that failed, and the reason.
+func (t *TranslateError) Error() string {
+	return fmt.Sprintf("unable to translate item %d: %s", t.Index, t.Err.Error())
+}
+
+// Unwrap returns the error returned by translate().
+func (t *TranslateError) Unwrap() error {
+	return t.Err
+}
+
 type pipelineJobStatus[IN any, OUT any] struct {
 	done   chan struct{}
 	cont   bool
@@ -39,18 +93,64 @@ type pipelineJobStatus[IN any, OUT any] struct {
 
 // TranslateSliceParallel iterates a slice in parallel and calls translate()
 // asynchronously.
-// translate() may return `datamodel.Continue` to continue iteration.
+// translate() may return `datamodel.Continue` (or `datamodel.Skip()`) to continue iteration.
 // translate() or result() may return `io.EOF` to break iteration.
 // Results are provided sequentially to result() in stable order from slice.
 func TranslateSliceParallel[IN any, OUT any](in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT]) error {
+	return translateSliceParallel(context.Background(), runtime.NumCPU(), in, translate, result, nil, false)
+}
+
+// TranslateSliceParallelWithSkips works the same way as TranslateSliceParallel, but also returns every item that
+// translate() skipped using `datamodel.Skip()`, sorted by index. Items skipped using `datamodel.Continue` have no
+// reason, and are not included.
+func TranslateSliceParallelWithSkips[IN any, OUT any](in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT]) ([]SkippedItem, error) {
+	var skipped []SkippedItem
+	err := translateSliceParallel(context.Background(), runtime.NumCPU(), in, translate, result, func(idx int, reason string) {
+		skipped = append(skipped, SkippedItem{Index: idx, Reason: reason})
+	}, false)
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Index < skipped[j].Index })
+	return skipped, err
+}
+
+// TranslateSliceParallelCtx works the same way as TranslateSliceParallel, but stops early when the supplied
+// context is cancelled. No new translate jobs are dispatched once the context is done, and ctx.Err() is returned.
+func TranslateSliceParallelCtx[IN any, OUT any](ctx context.Context, in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT]) error {
+	return translateSliceParallel(ctx, runtime.NumCPU(), in, translate, result, nil, false)
+}
+
+// TranslateSliceParallelWithConcurrency works the same way as TranslateSliceParallel, but no more than
+// n translate() calls will be in-flight at any one time. If n <= 0, then GOMAXPROCS is used.
+func TranslateSliceParallelWithConcurrency[IN any, OUT any](n int, in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT]) error {
+	return translateSliceParallel(context.Background(), n, in, translate, result, nil, false)
+}
+
+// TranslateSliceParallelPartial works the same way as TranslateSliceParallel, but when translate() fails, the
+// results of every item before the failing item are still provided to result() (in order), before a
+// *TranslateError, holding the index of the failing item, is returned. Items after the failing item are not
+// provided to result().
+func TranslateSliceParallelPartial[IN any, OUT any](in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT]) error {
+	return translateSliceParallel(context.Background(), runtime.NumCPU(), in, translate, result, nil, true)
+}
+
+// translateSliceParallel is the implementation of the TranslateSliceParallel functions, skip is called (while
+// holding a lock) for every item skipped with a reason, if it is not nil. If partial is set, every translate()
+// job already dispatched when translate() fails runs to completion, so the results before the failure can be
+// provided to result().
+func translateSliceParallel[IN any, OUT any](parent context.Context, concurrency int, in []IN, translate TranslateSliceFunc[IN, OUT], result ActionFunc[OUT], skip func(int, string), partial bool) error {
 	if in == nil {
 		return nil
 	}
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	defer cancel()
-	concurrency := runtime.NumCPU()
 	jobChan := make(chan *jobStatus[OUT], concurrency)
+	sem := make(chan struct{}, concurrency) // bounds in-flight translate() calls.
 	var reterr error
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -63,20 +163,47 @@ func TranslateSliceParallel[IN any, OUT any](in []IN, translate TranslateSliceFu
 			wg.Done()
 		}()
 		for idx, valueIn := range in {
+			// select picks randomly between ready cases, so check for cancellation first to stop
+			// dispatching as soon as translate() or result() has failed.
+			if ctx.Err() != nil {
+				return
+			}
 			j := &jobStatus[OUT]{
 				done: make(chan struct{}),
+				idx:  idx,
 			}
 			select {
 			case jobChan <- j:
 			case <-ctx.Done():
 				return
 			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				// the job will never run, so it must not be waited for.
+				j.cont = true
+				close(j.done)
+				return
+			}
 
 			wg.Add(1)
 			go func(idx int, valueIn IN) {
+				// release the semaphore before signalling the wait group, so nothing is left running
+				// once wg.Wait() returns.
+				defer wg.Done()
+				defer func() { <-sem }()
+				if ctx.Err() != nil && !partial {
+					return
+				}
 				valueOut, err := translate(idx, valueIn)
-				if err == Continue {
+				if isContinue(err) {
 					j.cont = true
+					var skipErr *SkipError
+					if skip != nil && errors.As(err, &skipErr) {
+						mu.Lock()
+						skip(idx, skipErr.Reason)
+						mu.Unlock()
+					}
 				} else if err != nil {
 					mu.Lock()
 					if reterr == nil {
@@ -84,16 +211,22 @@ func TranslateSliceParallel[IN any, OUT any](in []IN, translate TranslateSliceFu
 					}
 					mu.Unlock()
 					cancel()
-					wg.Done()
+					if partial {
+						j.err = err
+						close(j.done)
+					}
 					return
 				}
 				j.result = valueOut
 				close(j.done)
-				wg.Done()
 			}(idx, valueIn)
 		}
 	}()
 
+	if partial {
+		return partialResults(jobChan, result, cancel, &wg)
+	}
+
 	// Iterate jobChan as jobs complete.
 JOBLOOP:
 	for j := range jobChan {
@@ -120,9 +253,44 @@ JOBLOOP:
 	if reterr == io.EOF {
 		return nil
 	}
+	if reterr == nil {
+		return parent.Err()
+	}
 	return reterr
 }
 
+// partialResults provides the result of every job to result(), in order, until a job has failed. Every job
+// dispatched is waited for, as jobs are never abandoned in partial mode.
+func partialResults[OUT any](jobChan chan *jobStatus[OUT], result ActionFunc[OUT], cancel context.CancelFunc, wg *sync.WaitGroup) error {
+	var err error
+	for j := range jobChan {
+		<-j.done
+		if err != nil || j.cont {
+			continue
+		}
+		if j.err != nil {
+			if j.err != io.EOF {
+				err = &TranslateError{Index: j.idx, Err: j.err}
+			} else {
+				err = io.EOF
+			}
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		if rErr := result(j.result); rErr != nil {
+			err = rErr
+			cancel()
+		}
+	}
+	wg.Wait()
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
 // TranslateMapParallel iterates a `*orderedmap.Map` in parallel and calls translate()
 // asynchronously.
 // translate() or result() may return `io.EOF` to break iteration.
@@ -206,21 +374,129 @@ JOBLOOP:
 	return reterr
 }
 
+// TranslateNativeMapParallel iterates a Go map in parallel and calls translate()
+// asynchronously.
+// translate() may return `datamodel.Continue` (or `datamodel.Skip()`) to continue iteration.
+// translate() or result() may return `io.EOF` to break iteration.
+// Results are provided sequentially to result() along with their key, in no particular order.
+// (this is not named TranslateMapParallel, which already handles `*orderedmap.Map`).
+func TranslateNativeMapParallel[K comparable, V any, R any](in map[K]V, translate TranslateKeyValueFunc[K, V, R], result KeyResultFunc[K, R]) error {
+	if len(in) == 0 {
+		return nil
+	}
+
+	type mapResult struct {
+		key   K
+		value R
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	concurrency := runtime.NumCPU()
+	workChan := make(chan K)
+	resultChan := make(chan mapResult)
+	var reterr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	// Fan out keys to workers.
+	go func() {
+		defer close(workChan)
+		for k := range in {
+			select {
+			case workChan <- k:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Launch worker pool.
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range workChan {
+				value, err := translate(k, in[k])
+				if isContinue(err) {
+					continue
+				}
+				if err != nil {
+					mu.Lock()
+					if reterr == nil {
+						reterr = err
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+				select {
+				case resultChan <- mapResult{key: k, value: value}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	// Collect results sequentially.
+	var resultErr error
+	for r := range resultChan {
+		// keep draining after an error, so workers can exit.
+		if resultErr != nil || result == nil || ctx.Err() != nil {
+			continue
+		}
+		if err := result(r.key, r.value); err != nil {
+			resultErr = err
+			cancel()
+		}
+	}
+
+	if resultErr != nil {
+		if resultErr == io.EOF {
+			return nil
+		}
+		return resultErr
+	}
+	if reterr == io.EOF {
+		return nil
+	}
+	return reterr
+}
+
 // TranslatePipeline processes input sequentially through predicate(), sends to
 // translate() in parallel, then outputs in stable order.
-// translate() may return `datamodel.Continue` to continue iteration.
+// translate() may return `datamodel.Continue` (or `datamodel.Skip()`) to continue iteration.
 // Caller must close `in` channel to indicate EOF.
 // TranslatePipeline closes `out` channel to indicate EOF.
+//
+// If translate() returns an error, the pipeline stops reading from `in`, stops sending results to `out` (results
+// that have not been sent yet are dropped), waits for in-flight translate() calls to return, closes `out` exactly
+// once and returns the first error. The pipeline never blocks forever sending to `out` once an error has occurred,
+// so a consumer may stop reading as soon as it sees a failure. Producers writing to `in` should not assume every
+// value will be read after an error.
 func TranslatePipeline[IN any, OUT any](in <-chan IN, out chan<- OUT, translate TranslateFunc[IN, OUT]) error {
+	return TranslatePipelineWithConcurrency(runtime.NumCPU(), in, out, translate)
+}
+
+// TranslatePipelineWithConcurrency works the same way as TranslatePipeline, but runs n translate() workers.
+// If n <= 0, then GOMAXPROCS is used.
+func TranslatePipelineWithConcurrency[IN any, OUT any](n int, in <-chan IN, out chan<- OUT, translate TranslateFunc[IN, OUT]) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	concurrency := runtime.NumCPU()
+	concurrency := n
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
 	workChan := make(chan *pipelineJobStatus[IN, OUT])
 	resultChan := make(chan *pipelineJobStatus[IN, OUT])
 	var reterr error
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	defer wg.Wait()
 	wg.Add(1) // input goroutine.
 
 	// Launch worker pool.
@@ -235,7 +511,7 @@ func TranslatePipeline[IN any, OUT any](in <-chan IN, out chan<- OUT, translate
 						return
 					}
 					result, err := translate(j.input)
-					if err == Continue {
+					if isContinue(err) {
 						j.cont = true
 						close(j.done)
 						continue
@@ -291,19 +567,36 @@ func TranslatePipeline[IN any, OUT any](in <-chan IN, out chan<- OUT, translate
 		}
 	}()
 
-	// Collect results in stable order, send to output channel.
-	defer close(out)
+	firstErr := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return reterr
+	}
+
+	// Collect results in stable order, send to output channel. `out` is only closed once every worker has returned.
+	defer func() {
+		wg.Wait()
+		close(out)
+	}()
 	for j := range resultChan {
 		select {
 		case <-j.done:
 			if j.cont {
 				continue
 			}
-			out <- j.result
+			// once an error has occurred, results are dropped rather than sent.
+			if ctx.Err() != nil {
+				return firstErr()
+			}
+			select {
+			case out <- j.result:
+			case <-ctx.Done():
+				return firstErr()
+			}
 		case <-ctx.Done():
-			return reterr
+			return firstErr()
 		}
 	}
 
-	return reterr
+	return firstErr()
 }
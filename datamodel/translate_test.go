@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
 	"sort"
 	"strconv"
 	"sync"
@@ -176,6 +177,453 @@ func TestTranslateSliceParallel(t *testing.T) {
 	}
 }
 
+func TestTranslateSliceParallel_ErrorInResultStopsWorkers(t *testing.T) {
+	sl := make([]int, 100_000)
+	for i := range sl {
+		sl[i] = i
+	}
+
+	for _, resultErr := range []error{errors.New("Foobar"), io.EOF} {
+		t.Run(resultErr.Error(), func(t *testing.T) {
+			before := runtime.NumGoroutine()
+
+			var translateCounter int64
+			translateFunc := func(_, value int) (string, error) {
+				atomic.AddInt64(&translateCounter, 1)
+				return strconv.Itoa(value), nil
+			}
+			var resultCounter int
+			resultFunc := func(value string) error {
+				resultCounter++
+				if value == "5" {
+					return resultErr
+				}
+				return nil
+			}
+			err := datamodel.TranslateSliceParallel[int, string](sl, translateFunc, resultFunc)
+			if resultErr == io.EOF {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, resultErr)
+			}
+			assert.Equal(t, 6, resultCounter)
+
+			// dispatch stops promptly, rather than translating the rest of the slice.
+			translated := atomic.LoadInt64(&translateCounter)
+			assert.Less(t, translated, int64(len(sl)))
+
+			// every dispatch and worker goroutine has exited.
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+			assert.Equal(t, translated, atomic.LoadInt64(&translateCounter))
+		})
+	}
+}
+
+func TestTranslateSliceParallelWithSkips(t *testing.T) {
+	in := []string{"string", "object", "null", "integer", "tuple"}
+	var results []string
+	skipped, err := datamodel.TranslateSliceParallelWithSkips(in, func(idx int, v string) (string, error) {
+		switch v {
+		case "null":
+			return "", datamodel.Continue
+		case "object", "tuple":
+			return "", datamodel.Skip(fmt.Sprintf("unsupported type %s", v))
+		}
+		return v, nil
+	}, func(v string) error {
+		results = append(results, v)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"string", "integer"}, results)
+	assert.Equal(t, []datamodel.SkippedItem{
+		{Index: 1, Reason: "unsupported type object"},
+		{Index: 4, Reason: "unsupported type tuple"},
+	}, skipped)
+
+	// skips work the same way as Continue everywhere else.
+	var count int
+	err = datamodel.TranslateSliceParallel(in, func(idx int, v string) (string, error) {
+		if v != "string" {
+			return "", fmt.Errorf("wrapped: %w", datamodel.Skip("nope"))
+		}
+		return v, nil
+	}, func(v string) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "skipped: nope", datamodel.Skip("nope").Error())
+}
+
+func TestTranslateSliceParallelCtx(t *testing.T) {
+	const sliceSize = 10_000
+
+	var sl []int
+	for i := 0; i < sliceSize; i++ {
+		sl = append(sl, i)
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return fmt.Sprintf("foobar %d", value), nil
+		}
+		var resultCounter int
+		resultFunc := func(value string) error {
+			assert.Equal(t, fmt.Sprintf("foobar %d", resultCounter), value)
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelCtx[int, string](context.Background(), sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, sliceSize, resultCounter)
+	})
+
+	t.Run("Already cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var translateCounter int64
+		translateFunc := func(_, value int) (string, error) {
+			atomic.AddInt64(&translateCounter, 1)
+			return "", nil
+		}
+		err := datamodel.TranslateSliceParallelCtx[int, string](ctx, sl, translateFunc, nil)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, translateCounter)
+	})
+
+	t.Run("Cancelled during iteration", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var translateCounter int64
+		translateFunc := func(_, value int) (string, error) {
+			atomic.AddInt64(&translateCounter, 1)
+			return fmt.Sprintf("foobar %d", value), nil
+		}
+		var resultCounter int
+		resultFunc := func(value string) error {
+			// results delivered before cancellation must still be in order.
+			assert.Equal(t, fmt.Sprintf("foobar %d", resultCounter), value)
+			resultCounter++
+			if resultCounter == 100 {
+				cancel()
+			}
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelCtx[int, string](ctx, sl, translateFunc, resultFunc)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, resultCounter, sliceSize)
+		assert.Less(t, atomic.LoadInt64(&translateCounter), int64(sliceSize))
+	})
+
+	t.Run("Continue in translate", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			if value%2 == 0 {
+				return "", datamodel.Continue
+			}
+			return strconv.Itoa(value), nil
+		}
+		var resultCounter int
+		resultFunc := func(value string) error {
+			assert.Equal(t, strconv.Itoa(resultCounter*2+1), value)
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelCtx[int, string](context.Background(), sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, sliceSize/2, resultCounter)
+	})
+
+	t.Run("EOF in result", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return "foobar", nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string) error {
+			resultCounter++
+			return io.EOF
+		}
+		err := datamodel.TranslateSliceParallelCtx[int, string](context.Background(), sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCounter)
+	})
+}
+
+func TestTranslateSliceParallelWithConcurrency(t *testing.T) {
+	const sliceSize = 1000
+
+	var sl []int
+	for i := 0; i < sliceSize; i++ {
+		sl = append(sl, i)
+	}
+
+	for _, n := range []int{-1, 0, 1, 3} {
+		t.Run(fmt.Sprintf("Concurrency %d", n), func(t *testing.T) {
+			limit := int64(n)
+			if n <= 0 {
+				limit = int64(runtime.GOMAXPROCS(0))
+			}
+			var inFlight, maxInFlight int64
+			translateFunc := func(_, value int) (string, error) {
+				current := atomic.AddInt64(&inFlight, 1)
+				for {
+					seen := atomic.LoadInt64(&maxInFlight)
+					if current <= seen || atomic.CompareAndSwapInt64(&maxInFlight, seen, current) {
+						break
+					}
+				}
+				if value%2 == 0 {
+					time.Sleep(time.Microsecond)
+				}
+				atomic.AddInt64(&inFlight, -1)
+				if value%10 == 0 {
+					return "", datamodel.Continue
+				}
+				return strconv.Itoa(value), nil
+			}
+			var results []string
+			resultFunc := func(value string) error {
+				results = append(results, value)
+				return nil
+			}
+			err := datamodel.TranslateSliceParallelWithConcurrency[int, string](n, sl, translateFunc, resultFunc)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, maxInFlight, limit)
+			assert.Len(t, results, sliceSize-sliceSize/10)
+			assert.True(t, sort.SliceIsSorted(results, func(i, j int) bool {
+				a, _ := strconv.Atoi(results[i])
+				b, _ := strconv.Atoi(results[j])
+				return a < b
+			}))
+		})
+	}
+
+	t.Run("Error in translate", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return "", errors.New("Foobar")
+		}
+		err := datamodel.TranslateSliceParallelWithConcurrency[int, string](2, sl, translateFunc, nil)
+		require.ErrorContains(t, err, "Foobar")
+	})
+
+	t.Run("EOF in result", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return "foobar", nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string) error {
+			resultCounter++
+			return io.EOF
+		}
+		err := datamodel.TranslateSliceParallelWithConcurrency[int, string](2, sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCounter)
+	})
+}
+
+func TestTranslateSliceParallelPartial(t *testing.T) {
+	const sliceSize = 10_000
+	const failAt = 5_000
+
+	var sl []int
+	for i := 0; i < sliceSize; i++ {
+		sl = append(sl, i)
+	}
+
+	t.Run("Error in translate", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			if value%3 == 0 {
+				time.Sleep(time.Microsecond)
+			}
+			if value == failAt {
+				return "", errors.New("Foobar")
+			}
+			return strconv.Itoa(value), nil
+		}
+		var results []string
+		resultFunc := func(value string) error {
+			results = append(results, value)
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		require.ErrorContains(t, err, "Foobar")
+
+		var translateErr *datamodel.TranslateError
+		require.ErrorAs(t, err, &translateErr)
+		assert.Equal(t, failAt, translateErr.Index)
+		assert.EqualError(t, err, "unable to translate item 5000: Foobar")
+
+		require.Len(t, results, failAt)
+		for i, value := range results {
+			assert.Equal(t, strconv.Itoa(i), value)
+		}
+	})
+
+	t.Run("Multiple errors reports first", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			if value >= failAt {
+				return "", fmt.Errorf("failed %d", value)
+			}
+			time.Sleep(time.Microsecond)
+			return strconv.Itoa(value), nil
+		}
+		var results []string
+		resultFunc := func(value string) error {
+			results = append(results, value)
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		var translateErr *datamodel.TranslateError
+		require.ErrorAs(t, err, &translateErr)
+		assert.Equal(t, failAt, translateErr.Index)
+		assert.Len(t, results, failAt)
+	})
+
+	t.Run("Skips before error", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			if value == 10 {
+				return "", errors.New("Foobar")
+			}
+			if value%2 == 0 {
+				return "", datamodel.Continue
+			}
+			return strconv.Itoa(value), nil
+		}
+		var results []string
+		resultFunc := func(value string) error {
+			results = append(results, value)
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		require.Error(t, err)
+		assert.Equal(t, []string{"1", "3", "5", "7", "9"}, results)
+	})
+
+	t.Run("EOF in translate", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			if value == failAt {
+				return "", io.EOF
+			}
+			return strconv.Itoa(value), nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string) error {
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, failAt, resultCounter)
+	})
+
+	t.Run("Error in result", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return strconv.Itoa(value), nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string) error {
+			resultCounter++
+			if resultCounter == 10 {
+				return errors.New("Foobar")
+			}
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		require.EqualError(t, err, "Foobar")
+		assert.Equal(t, 10, resultCounter)
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		translateFunc := func(_, value int) (string, error) {
+			return strconv.Itoa(value), nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string) error {
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateSliceParallelPartial[int, string](sl, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, sliceSize, resultCounter)
+	})
+}
+
+func BenchmarkTranslateSliceParallelWithConcurrency(b *testing.B) {
+	for _, mapSize := range []int{100, 10_000, 100_000} {
+		var sl []int
+		for i := 0; i < mapSize; i++ {
+			sl = append(sl, i)
+		}
+		for _, n := range []int{1, 4, 0} {
+			b.Run(fmt.Sprintf("MapSize %d Concurrency %d", mapSize, n), func(b *testing.B) {
+				// peak goroutines stays bounded by the concurrency limit, regardless of MapSize.
+				var maxGoroutines int64
+				translateFunc := func(_, value int) (string, error) {
+					if value%100 == 0 {
+						if g := int64(runtime.NumGoroutine()); g > atomic.LoadInt64(&maxGoroutines) {
+							atomic.StoreInt64(&maxGoroutines, g)
+						}
+					}
+					return strconv.Itoa(value), nil
+				}
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = datamodel.TranslateSliceParallelWithConcurrency[int, string](n, sl, translateFunc, func(string) error { return nil })
+				}
+				b.ReportMetric(float64(atomic.LoadInt64(&maxGoroutines)), "max-goroutines")
+			})
+		}
+	}
+}
+
+func TestTranslatePipelineWithConcurrency(t *testing.T) {
+	const itemCount = 1000
+	const n = 2
+
+	in := make(chan int)
+	out := make(chan string)
+	go func() {
+		defer close(in)
+		for i := 0; i < itemCount; i++ {
+			in <- i
+		}
+	}()
+
+	var resultCounter int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for result := range out {
+			assert.Equal(t, strconv.Itoa(resultCounter), result)
+			resultCounter++
+		}
+	}()
+
+	var inFlight, maxInFlight int64
+	err := datamodel.TranslatePipelineWithConcurrency[int, string](n, in, out,
+		func(value int) (string, error) {
+			current := atomic.AddInt64(&inFlight, 1)
+			for {
+				seen := atomic.LoadInt64(&maxInFlight)
+				if current <= seen || atomic.CompareAndSwapInt64(&maxInFlight, seen, current) {
+					break
+				}
+			}
+			atomic.AddInt64(&inFlight, -1)
+			return strconv.Itoa(value), nil
+		},
+	)
+	<-done
+	require.NoError(t, err)
+	assert.Equal(t, itemCount, resultCounter)
+	assert.LessOrEqual(t, maxInFlight, int64(n))
+}
+
 func TestTranslateMapParallel(t *testing.T) {
 	const mapSize = 1000
 
@@ -301,6 +749,151 @@ func TestTranslateMapParallel(t *testing.T) {
 	})
 }
 
+func TestTranslateNativeMapParallel(t *testing.T) {
+	const mapSize = 1000
+
+	t.Run("Happy path", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i + 1000
+		}
+
+		var translateCounter int64
+		translateFunc := func(key string, value int) (string, error) {
+			atomic.AddInt64(&translateCounter, 1)
+			return fmt.Sprintf("foobar %d", value), nil
+		}
+		results := make(map[string]string)
+		resultFunc := func(key string, value string) error {
+			results[key] = value
+			return nil
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, int64(mapSize), translateCounter)
+		assert.Equal(t, mapSize, len(results))
+		for k, v := range m {
+			assert.Equal(t, fmt.Sprintf("foobar %d", v), results[k])
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var m map[string]int
+		var translateCounter int64
+		translateFunc := func(_ string, _ int) (string, error) {
+			atomic.AddInt64(&translateCounter, 1)
+			return "", nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string, _ string) error {
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Zero(t, translateCounter)
+		assert.Zero(t, resultCounter)
+	})
+
+	t.Run("Error in translate", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i + 1000
+		}
+
+		translateFunc := func(_ string, _ int) (string, error) {
+			return "", errors.New("Foobar")
+		}
+		resultFunc := func(_ string, _ string) error {
+			t.Fatal("Expected no call to resultFunc()")
+			return nil
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.ErrorContains(t, err, "Foobar")
+	})
+
+	t.Run("Error in result", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i + 1000
+		}
+
+		translateFunc := func(_ string, _ int) (string, error) {
+			return "", nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string, _ string) error {
+			resultCounter++
+			return errors.New("Foobar")
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.ErrorContains(t, err, "Foobar")
+		assert.Equal(t, 1, resultCounter)
+	})
+
+	t.Run("EOF in translate", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i + 1000
+		}
+
+		translateFunc := func(_ string, _ int) (string, error) {
+			return "", io.EOF
+		}
+		resultFunc := func(_ string, _ string) error {
+			t.Fatal("Expected no call to resultFunc()")
+			return nil
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.NoError(t, err)
+	})
+
+	t.Run("EOF in result", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i + 1000
+		}
+
+		translateFunc := func(_ string, _ int) (string, error) {
+			return "", nil
+		}
+		var resultCounter int
+		resultFunc := func(_ string, _ string) error {
+			resultCounter++
+			return io.EOF
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, string](m, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCounter)
+	})
+
+	t.Run("Continue in translate", func(t *testing.T) {
+		m := make(map[string]int)
+		for i := 0; i < mapSize; i++ {
+			m[fmt.Sprintf("key%d", i)] = i
+		}
+
+		var translateCounter int64
+		translateFunc := func(_ string, value int) (int, error) {
+			atomic.AddInt64(&translateCounter, 1)
+			if value%2 == 0 {
+				return 0, datamodel.Continue
+			}
+			return value, nil
+		}
+		var resultCounter int
+		resultFunc := func(key string, value int) error {
+			assert.Equal(t, fmt.Sprintf("key%d", value), key)
+			resultCounter++
+			return nil
+		}
+		err := datamodel.TranslateNativeMapParallel[string, int, int](m, translateFunc, resultFunc)
+		require.NoError(t, err)
+		assert.Equal(t, int64(mapSize), translateCounter)
+		assert.Equal(t, mapSize/2, resultCounter)
+	})
+}
+
 func TestTranslatePipeline(t *testing.T) {
 	testCases := []struct {
 		ItemCount int
@@ -546,3 +1139,90 @@ func TestTranslatePipeline(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatePipeline_ClosesOutAfterWorkers(t *testing.T) {
+	in := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+	close(in)
+	out := make(chan int)
+	var started, finished atomic.Int32
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- datamodel.TranslatePipelineWithConcurrency(4, in, out, func(value int) (int, error) {
+			started.Add(1)
+			defer finished.Add(1)
+			if value == 0 {
+				// give the next value time to start translating.
+				time.Sleep(10 * time.Millisecond)
+				return 0, errors.New("boom")
+			}
+			// this call is still in flight when the error is returned.
+			time.Sleep(100 * time.Millisecond)
+			return value, nil
+		})
+	}()
+
+	for range out {
+	}
+	// every translate() call has returned by the time out is closed.
+	assert.Equal(t, started.Load(), finished.Load())
+	require.ErrorContains(t, <-errChan, "boom")
+}
+
+func TestTranslatePipeline_AbandonedConsumer(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	in := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+	close(in)
+	out := make(chan int)
+	failed := make(chan struct{})
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- datamodel.TranslatePipelineWithConcurrency(4, in, out, func(value int) (int, error) {
+			if value == 2 {
+				// this result completes after the consumer has stopped reading.
+				time.Sleep(10 * time.Millisecond)
+			}
+			if value == 3 {
+				// the consumer learns about the failure and stops reading, before the error is returned.
+				close(failed)
+				time.Sleep(50 * time.Millisecond)
+				return 0, errors.New("boom")
+			}
+			return value, nil
+		})
+	}()
+
+	func() {
+		for {
+			select {
+			case <-out:
+			case <-failed:
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-errChan:
+		require.ErrorContains(t, err, "boom")
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline blocked sending to an abandoned consumer")
+	}
+
+	// out has been closed, and no further results are sent.
+	_, ok := <-out
+	assert.False(t, ok)
+
+	// every pipeline goroutine has exited.
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
@@ -1361,9 +1361,10 @@ func TestDocument_Issue264(t *testing.T) {
 
 	_, _ = d.BuildV3Model()
 
-	_, _, _, errs := d.RenderAndReload() // code panics here
-	assert.Len(t, errs, 1)
-	assert.Equal(t, "yaml: cannot decode !!float `-999.99` as a !!int", errs[0].Error())
+	rend, _, _, errs := d.RenderAndReload()
+	assert.Empty(t, errs)
+	assert.Contains(t, string(rend), "-999.99")
+	assert.Contains(t, string(rend), "0.01")
 }
 
 func TestDocument_Issue269(t *testing.T) {
@@ -15,7 +15,72 @@ type CircularReferenceResult struct {
 	IsArrayResult       bool   // if this result comes from an array loop.
 	PolymorphicType     string // which type of polymorphic loop is this? (oneOf, anyOf, allOf)
 	IsPolymorphicResult bool   // if this result comes from a polymorphic loop.
-	IsInfiniteLoop      bool   // if all the definitions in the reference loop are marked as required, this is an infinite circular reference, thus is not allowed.
+	IsInfiniteLoop      bool   // if all the definitions in the reference loop are marked as required (and arrays must have items), this is an infinite circular reference, thus is not allowed.
+}
+
+// CircularReferenceType classifies a circular reference by how (or if) the recursion can end.
+type CircularReferenceType string
+
+const (
+	// InfiniteCircularReference is a loop where every step is a required property (or a required array that must
+	// contain at least one item), the structure can never end, so it is an error.
+	InfiniteCircularReference CircularReferenceType = "infinite"
+	// ArrayCircularReference is a loop through an array, which ends with an empty array.
+	ArrayCircularReference CircularReferenceType = "array"
+	// PolymorphicCircularReference is a loop through oneOf, anyOf or allOf.
+	PolymorphicCircularReference CircularReferenceType = "polymorphic"
+	// OptionalCircularReference is a loop through an optional property, which ends when the property is omitted.
+	OptionalCircularReference CircularReferenceType = "optional"
+)
+
+// Classification returns the type of the circular reference. Only an InfiniteCircularReference is an error,
+// every other type is legal recursion.
+func (c *CircularReferenceResult) Classification() CircularReferenceType {
+	switch {
+	case c.IsInfiniteLoop:
+		return InfiniteCircularReference
+	case c.IsArrayResult:
+		return ArrayCircularReference
+	case c.IsPolymorphicResult:
+		return PolymorphicCircularReference
+	}
+	return OptionalCircularReference
+}
+
+// IsSafe returns true if the circular reference is legal recursion (it is not an infinite loop).
+func (c *CircularReferenceResult) IsSafe() bool {
+	return !c.IsInfiniteLoop
+}
+
+// CircularReferenceError is returned when a circular reference is found. Journey contains the definition of every
+// reference that makes up the loop, in order, for example '#/components/schemas/A', '#/components/schemas/B',
+// '#/components/schemas/A'.
+type CircularReferenceError struct {
+	Err               error
+	Journey           []string
+	CircularReference *CircularReferenceResult
+}
+
+// NewCircularReferenceError creates a new CircularReferenceError for the supplied result, wrapping err.
+func NewCircularReferenceError(err error, result *CircularReferenceResult) *CircularReferenceError {
+	return &CircularReferenceError{Err: err, Journey: result.GenerateJourneyDefinitions(), CircularReference: result}
+}
+
+func (c *CircularReferenceError) Error() string {
+	return c.Err.Error()
+}
+
+func (c *CircularReferenceError) Unwrap() error {
+	return c.Err
+}
+
+// GenerateJourneyDefinitions returns the definition of every reference in the journey, in order.
+func (c *CircularReferenceResult) GenerateJourneyDefinitions() []string {
+	journey := make([]string, 0, len(c.Journey))
+	for _, ref := range c.Journey {
+		journey = append(journey, ref.Definition)
+	}
+	return journey
 }
 
 // GenerateJourneyPath generates a string representation of the journey taken to find the circular reference.
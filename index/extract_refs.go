@@ -236,7 +236,7 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 
 					// determine absolute path to this definition
 					var defRoot string
-					if strings.HasPrefix(index.specAbsolutePath, "http") {
+					if utils.IsRemoteLocation(index.specAbsolutePath) {
 						defRoot = index.specAbsolutePath
 					} else {
 						defRoot = filepath.Dir(index.specAbsolutePath)
@@ -249,7 +249,7 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 							fullDefinitionPath = fmt.Sprintf("%s#/%s", index.specAbsolutePath, uri[1])
 							componentName = value
 						} else {
-							if strings.HasPrefix(uri[0], "http") {
+							if utils.IsRemoteLocation(uri[0]) {
 								fullDefinitionPath = value
 								componentName = fmt.Sprintf("#/%s", uri[1])
 							} else {
@@ -269,7 +269,7 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 										// if the index has a base URL, use that to resolve the path.
 										if index.config.BaseURL != nil && !filepath.IsAbs(defRoot) {
 											var u url.URL
-											if strings.HasPrefix(defRoot, "http") {
+											if utils.IsRemoteLocation(defRoot) {
 												up, _ := url.Parse(defRoot)
 												up.Path = utils.ReplaceWindowsDriveWithLinuxPath(filepath.Dir(up.Path))
 												u = *up
@@ -293,12 +293,12 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 							}
 						}
 					} else {
-						if strings.HasPrefix(uri[0], "http") {
+						if utils.IsRemoteLocation(uri[0]) {
 							fullDefinitionPath = value
 						} else {
 							// is it a relative file include?
 							if !strings.Contains(uri[0], "#") {
-								if strings.HasPrefix(defRoot, "http") {
+								if utils.IsRemoteLocation(defRoot) {
 									if !filepath.IsAbs(uri[0]) {
 										u, _ := url.Parse(defRoot)
 										pathDir := filepath.Dir(u.Path)
@@ -340,6 +340,11 @@ func (index *SpecIndex) ExtractRefs(node, parent *yaml.Node, seenPath []string,
 						}
 					}
 
+					// logically identical local paths share the same key, so they are only looked up once.
+					if fullDefinitionPath != "" && !utils.IsRemoteLocation(fullDefinitionPath) {
+						fullDefinitionPath = normalizeLocalPath(fullDefinitionPath)
+					}
+
 					_, p := utils.ConvertComponentIdIntoFriendlyPathSearch(componentName)
 
 					ref := &Reference{
@@ -4,12 +4,14 @@
 package index
 
 import (
+	"context"
 	"io/fs"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/pb33f/libopenapi/datamodel"
 
@@ -70,6 +72,33 @@ type SpecIndexConfig struct {
 	// deprecated: Use the Rolodex instead
 	RemoteURLHandler func(url string) (*http.Response, error)
 
+	// HTTPClient is the http.Client used by the RemoteFS to fetch remote documents. If not set, a default client
+	// is used. If RemoteURLHandler is set, it takes precedence over the HTTPClient.
+	HTTPClient *http.Client
+
+	// RemoteCacheDir is a directory used by the RemoteFS to cache fetched remote documents, keyed by URL. Cached
+	// documents are revalidated using ETag or Last-Modified values when the server provides them, and fetched
+	// again when it does not. Cached documents are used if the server cannot be reached. The cache is not used if
+	// RemoteURLHandler is set.
+	RemoteCacheDir string
+
+	// RemoteRetries is the number of times the RemoteFS will retry fetching a remote document that failed with a
+	// network error or a 5xx status. 4xx responses are never retried. The default of 0 means no retries.
+	RemoteRetries int
+
+	// RemoteRetryBackoff is the wait before the first retry, doubling for each retry after that. A Retry-After
+	// header on the failed response is used instead, when present. Defaults to one second.
+	RemoteRetryBackoff time.Duration
+
+	// RemoteRetryMaxWait is the longest wait before a retry, including waits requested by a Retry-After header, so
+	// a server cannot stall indexing. Defaults to 30 seconds.
+	RemoteRetryMaxWait time.Duration
+
+	// Context is used for every remote document fetched by the RemoteFS, so a cancelled context (or an expired
+	// deadline) cancels in-flight requests, retries and waits for other fetches. If not set, fetches are never
+	// cancelled. Custom RemoteURLHandler functions are not passed the context, they can't be cancelled.
+	Context context.Context
+
 	// FSHandler is an entity that implements the `fs.FS` interface that will be used to fetch local or remote documents.
 	// This is useful if you want to use a custom file system handler, or if you want to use a custom http client or
 	// custom network implementation for a lookup.
@@ -140,6 +169,11 @@ type SpecIndexConfig struct {
 	// this is disabled by default, which means array circular references will be checked.
 	IgnoreArrayCircularReferences bool
 
+	// MaxResolveDepth limits how many references deep the resolver will follow a chain of references from a single
+	// root reference. When the limit is exceeded, a resolving error naming the reference is returned and the chain
+	// is not followed any further. The default of 0 means there is no limit.
+	MaxResolveDepth int
+
 	// SkipDocumentCheck will skip the document check when building the index. A document check will look for an 'openapi'
 	// or 'swagger' node in the root of the document. If it's not found, then the document is not a valid OpenAPI or
 	// the file is a JSON Schema. To allow JSON Schema files to be included set this to true.
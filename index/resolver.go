@@ -51,6 +51,10 @@ func (r *ResolvingError) Error() string {
 	return strings.Join(msgs, "\n")
 }
 
+func (r *ResolvingError) Unwrap() error {
+	return r.ErrorRef
+}
+
 // Resolver will use a *index.SpecIndex to stitch together a resolved root tree using all the discovered
 // references in the doc.
 type Resolver struct {
@@ -67,6 +71,8 @@ type Resolver struct {
 	IgnorePoly             bool
 	IgnoreArray            bool
 	circChecked            bool
+	maxDepth               int
+	depthExceeded          map[string]bool
 }
 
 // NewResolver will create a new resolver from a *index.SpecIndex
@@ -78,6 +84,9 @@ func NewResolver(index *SpecIndex) *Resolver {
 		specIndex:    index,
 		resolvedRoot: index.GetRootNode(),
 	}
+	if index.config != nil {
+		r.maxDepth = index.config.MaxResolveDepth
+	}
 	index.resolver = r
 	return r
 }
@@ -200,7 +209,8 @@ func (resolver *Resolver) Resolve() []*ResolvingError {
 
 		if !resolver.circChecked {
 			resolver.resolvingErrors = append(resolver.resolvingErrors, &ResolvingError{
-				ErrorRef:          fmt.Errorf("infinite circular reference detected: %s", circRef.Start.Definition),
+				ErrorRef: NewCircularReferenceError(
+					fmt.Errorf("infinite circular reference detected: %s", circRef.Start.Definition), circRef),
 				Node:              circRef.ParentNode,
 				Path:              circRef.GenerateJourneyPath(),
 				CircularReference: circRef,
@@ -224,7 +234,8 @@ func (resolver *Resolver) CheckForCircularReferences() []*ResolvingError {
 		}
 		if !resolver.circChecked {
 			resolver.resolvingErrors = append(resolver.resolvingErrors, &ResolvingError{
-				ErrorRef:          fmt.Errorf("infinite circular reference detected: %s", circRef.Start.Name),
+				ErrorRef: NewCircularReferenceError(
+					fmt.Errorf("infinite circular reference detected: %s", circRef.Start.Name), circRef),
 				Node:              circRef.ParentNode,
 				Path:              circRef.GenerateJourneyPath(),
 				CircularReference: circRef,
@@ -334,6 +345,10 @@ func (resolver *Resolver) VisitReference(ref *Reference, seen map[string]bool, j
 	}
 
 	journey = append(journey, ref)
+	if resolver.maxDepth > 0 && len(journey) > resolver.maxDepth {
+		resolver.recordDepthExceeded(ref, journey)
+		return ref.Node.Content
+	}
 	seenRelatives := make(map[int]bool)
 	relatives := resolver.extractRelatives(ref, ref.Node, nil, seen, journey, seenRelatives, resolve, 0)
 
@@ -414,6 +429,28 @@ func (resolver *Resolver) VisitReference(ref *Reference, seen map[string]bool, j
 	return ref.Node.Content
 }
 
+// recordDepthExceeded adds a resolving error for a reference found deeper than the maximum resolve depth, each
+// reference is only reported once.
+func (resolver *Resolver) recordDepthExceeded(ref *Reference, journey []*Reference) {
+	if resolver.depthExceeded == nil {
+		resolver.depthExceeded = make(map[string]bool)
+	}
+	if resolver.depthExceeded[ref.FullDefinition] {
+		return
+	}
+	resolver.depthExceeded[ref.FullDefinition] = true
+	path := make([]string, len(journey))
+	for i, j := range journey {
+		path[i] = j.Definition
+	}
+	resolver.resolvingErrors = append(resolver.resolvingErrors, &ResolvingError{
+		ErrorRef: fmt.Errorf("maximum resolve depth of %d exceeded resolving reference '%s'",
+			resolver.maxDepth, ref.Definition),
+		Node: ref.Node,
+		Path: strings.Join(path, " -> "),
+	})
+}
+
 func (resolver *Resolver) isInfiniteCircularDependency(ref *Reference, visitedDefinitions map[string]bool,
 	initialRef *Reference,
 ) (bool, map[string]bool) {
@@ -532,11 +569,11 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 				if len(exp) == 2 {
 					definition = fmt.Sprintf("#/%s", exp[1])
 					if exp[0] != "" {
-						if strings.HasPrefix(exp[0], "http") {
+						if utils.IsRemoteLocation(exp[0]) {
 							fullDef = value
 						} else {
 
-							if strings.HasPrefix(ref.FullDefinition, "http") {
+							if utils.IsRemoteLocation(ref.FullDefinition) {
 
 								// split the http URI into parts
 								httpExp := strings.Split(ref.FullDefinition, "#/")
@@ -562,7 +599,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 						}
 					} else {
 						// local component, full def is based on passed in ref
-						if strings.HasPrefix(ref.FullDefinition, "http") {
+						if utils.IsRemoteLocation(ref.FullDefinition) {
 
 							// split the http URI into parts
 							httpExp := strings.Split(ref.FullDefinition, "#/")
@@ -584,7 +621,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 					definition = value
 
 					// if the reference is a http link
-					if strings.HasPrefix(value, "http") {
+					if utils.IsRemoteLocation(value) {
 						fullDef = value
 					} else {
 
@@ -592,7 +629,7 @@ func (resolver *Resolver) extractRelatives(ref *Reference, node, parent *yaml.No
 						fileDef := strings.Split(ref.FullDefinition, "#/")
 
 						// is the file def a http link?
-						if strings.HasPrefix(fileDef[0], "http") {
+						if utils.IsRemoteLocation(fileDef[0]) {
 							u, _ := url.Parse(fileDef[0])
 							path, _ := filepath.Abs(utils.CheckPathOverlap(filepath.Dir(u.Path), exp[0], string(filepath.Separator)))
 							u.Path = utils.ReplaceWindowsDriveWithLinuxPath(path)
@@ -813,9 +850,9 @@ func (resolver *Resolver) buildDefPath(ref *Reference, l string) string {
 	exp := strings.Split(l, "#/")
 	if len(exp) == 2 {
 		if exp[0] != "" {
-			if !strings.HasPrefix(exp[0], "http") {
+			if !utils.IsRemoteLocation(exp[0]) {
 				if !filepath.IsAbs(exp[0]) {
-					if strings.HasPrefix(ref.FullDefinition, "http") {
+					if utils.IsRemoteLocation(ref.FullDefinition) {
 
 						u, _ := url.Parse(ref.FullDefinition)
 						p, _ := filepath.Abs(utils.CheckPathOverlap(filepath.Dir(u.Path), exp[0], string(filepath.Separator)))
@@ -848,7 +885,7 @@ func (resolver *Resolver) buildDefPath(ref *Reference, l string) string {
 				}
 			}
 		} else {
-			if strings.HasPrefix(ref.FullDefinition, "http") {
+			if utils.IsRemoteLocation(ref.FullDefinition) {
 				u, _ := url.Parse(ref.FullDefinition)
 				u.Fragment = ""
 				def = fmt.Sprintf("%s#/%s", u.String(), exp[1])
@@ -863,12 +900,12 @@ func (resolver *Resolver) buildDefPath(ref *Reference, l string) string {
 			}
 		}
 	} else {
-		if strings.HasPrefix(l, "http") {
+		if utils.IsRemoteLocation(l) {
 			def = l
 		} else {
 
 			// check if were dealing with a remote file
-			if strings.HasPrefix(ref.FullDefinition, "http") {
+			if utils.IsRemoteLocation(ref.FullDefinition) {
 
 				// split the url.
 				u, _ := url.Parse(ref.FullDefinition)
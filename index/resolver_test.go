@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -170,16 +171,123 @@ components:
 	resolver := NewResolver(idx)
 	assert.NotNil(t, resolver)
 
+	// a required array can be empty, so this is legal recursion rather than an infinite loop.
 	circ := resolver.CheckForCircularReferences()
-	assert.Len(t, circ, 1)
-	assert.Len(t, resolver.GetResolvingErrors(), 1) // infinite loop is a resolving error.
-	assert.Len(t, resolver.GetInfiniteCircularReferences(), 1)
-	assert.True(t, resolver.GetInfiniteCircularReferences()[0].IsArrayResult)
+	assert.Len(t, circ, 0)
+	assert.Len(t, resolver.GetInfiniteCircularReferences(), 0)
+	assert.Len(t, resolver.GetSafeCircularReferences(), 1)
+	assert.True(t, resolver.GetSafeCircularReferences()[0].IsArrayResult)
+	assert.Equal(t, ArrayCircularReference, resolver.GetSafeCircularReferences()[0].Classification())
 
 	_, err := yaml.Marshal(resolver.resolvedRoot)
 	assert.NoError(t, err)
 }
 
+func TestResolver_CheckForCircularReferences_CatchArray_MinItems(t *testing.T) {
+	circular := []byte(`openapi: 3.0.0
+components:
+  schemas:
+    ProductCategory:
+      type: "object"
+      properties:
+        children:
+          type: "array"
+          minItems: 1
+          items:
+            $ref: "#/components/schemas/ProductCategory"
+      required:
+        - "children"`)
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(circular, &rootNode)
+
+	idx := NewSpecIndexWithConfig(&rootNode, CreateClosedAPIIndexConfig())
+	resolver := NewResolver(idx)
+
+	// an array that must have an item can never end.
+	circ := resolver.CheckForCircularReferences()
+	assert.Len(t, circ, 1)
+	assert.Len(t, resolver.GetInfiniteCircularReferences(), 1)
+	assert.Equal(t, InfiniteCircularReference, resolver.GetInfiniteCircularReferences()[0].Classification())
+	assert.False(t, resolver.GetInfiniteCircularReferences()[0].IsSafe())
+}
+
+func TestResolver_CheckForCircularReferences_Classification(t *testing.T) {
+	circular := []byte(`openapi: 3.1.0
+components:
+  schemas:
+    Optional:
+      type: object
+      properties:
+        next:
+          $ref: "#/components/schemas/Optional"
+    Poly:
+      type: object
+      properties:
+        next:
+          oneOf:
+            - $ref: "#/components/schemas/Poly"
+            - type: "null"
+    Infinite:
+      type: object
+      required: [me]
+      properties:
+        me:
+          $ref: "#/components/schemas/Infinite"`)
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(circular, &rootNode)
+
+	idx := NewSpecIndexWithConfig(&rootNode, CreateClosedAPIIndexConfig())
+	resolver := NewResolver(idx)
+
+	circ := resolver.CheckForCircularReferences()
+	assert.Len(t, circ, 1)
+
+	classes := make(map[string]CircularReferenceType)
+	for _, ref := range append(resolver.GetSafeCircularReferences(), resolver.GetInfiniteCircularReferences()...) {
+		classes[ref.Start.Name] = ref.Classification()
+	}
+	assert.Equal(t, map[string]CircularReferenceType{
+		"Optional": OptionalCircularReference,
+		"Poly":     PolymorphicCircularReference,
+		"Infinite": InfiniteCircularReference,
+	}, classes)
+}
+
+func TestResolver_CheckForCircularReferences_Journey(t *testing.T) {
+	circular := []byte(`openapi: 3.0.0
+components:
+  schemas:
+    A:
+      type: object
+      required: [b]
+      properties:
+        b:
+          $ref: "#/components/schemas/B"
+    B:
+      type: object
+      required: [a]
+      properties:
+        a:
+          $ref: "#/components/schemas/A"`)
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(circular, &rootNode)
+
+	idx := NewSpecIndexWithConfig(&rootNode, CreateClosedAPIIndexConfig())
+	resolver := NewResolver(idx)
+
+	circ := resolver.CheckForCircularReferences()
+	assert.NotEmpty(t, circ)
+
+	var circErr *CircularReferenceError
+	assert.True(t, errors.As(circ[0], &circErr))
+	assert.Equal(t, []string{"#/components/schemas/B", "#/components/schemas/A", "#/components/schemas/B"},
+		circErr.Journey)
+	assert.Same(t, circ[0].CircularReference, circErr.CircularReference)
+
+	// the message is unchanged.
+	assert.Contains(t, circ[0].Error(), "infinite circular reference detected: B")
+}
+
 func TestResolver_CheckForCircularReferences_IgnoreArray(t *testing.T) {
 	circular := []byte(`openapi: 3.0.0
 components:
@@ -434,8 +542,13 @@ func TestResolver_CircularReferencesRequiredInvalid(t *testing.T) {
 	resolver := NewResolver(idx)
 	assert.NotNil(t, resolver)
 
+	// every loop passes through a required array without minItems, which can be empty, so each is legal.
 	circ := resolver.CheckForCircularReferences()
-	assert.Len(t, circ, 2)
+	assert.Len(t, circ, 0)
+	assert.Len(t, resolver.GetSafeCircularReferences(), 2)
+	for _, ref := range resolver.GetSafeCircularReferences() {
+		assert.Equal(t, ArrayCircularReference, ref.Classification())
+	}
 
 	_, err := yaml.Marshal(resolver.resolvedRoot)
 	assert.NoError(t, err)
@@ -1443,3 +1556,56 @@ components:
 	assert.Len(t, errs, 0)
 
 }
+
+func TestResolver_MaxResolveDepth(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("openapi: 3.1.0\ncomponents:\n  schemas:\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "    Schema%d:\n      type: object\n      properties:\n        next:\n"+
+			"          $ref: '#/components/schemas/Schema%d'\n", i, i+1)
+	}
+	b.WriteString("    Schema30:\n      type: string\n")
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(b.String()), &rootNode)
+
+	// without a limit, the whole chain resolves.
+	idx := NewSpecIndexWithConfig(&rootNode, CreateClosedAPIIndexConfig())
+	assert.Len(t, NewResolver(idx).Resolve(), 0)
+
+	_ = yaml.Unmarshal([]byte(b.String()), &rootNode)
+	cf := CreateClosedAPIIndexConfig()
+	cf.MaxResolveDepth = 10
+	idx = NewSpecIndexWithConfig(&rootNode, cf)
+	errs := NewResolver(idx).Resolve()
+	assert.NotEmpty(t, errs)
+	assert.Equal(t, "maximum resolve depth of 10 exceeded resolving reference '#/components/schemas/Schema11'",
+		errs[0].ErrorRef.Error())
+	assert.Equal(t, 10, strings.Count(errs[0].Path, " -> "))
+}
+
+func TestResolver_MaxResolveDepth_ResetPerRoot(t *testing.T) {
+	// two short chains, that are each within the limit.
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    A:
+      $ref: '#/components/schemas/B'
+    B:
+      $ref: '#/components/schemas/C'
+    C:
+      type: string
+    X:
+      $ref: '#/components/schemas/Y'
+    Y:
+      $ref: '#/components/schemas/Z'
+    Z:
+      type: string`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+	cf := CreateClosedAPIIndexConfig()
+	cf.MaxResolveDepth = 2
+	idx := NewSpecIndexWithConfig(&rootNode, cf)
+	assert.Len(t, NewResolver(idx).Resolve(), 0)
+}
@@ -6,6 +6,7 @@ package index
 import (
 	"errors"
 	"fmt"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 	"io"
 	"io/fs"
@@ -63,6 +64,7 @@ type Rolodex struct {
 	circChecked                bool
 	indexConfig                *SpecIndexConfig
 	indexingDuration           time.Duration
+	circularCheckDuration      time.Duration
 	indexes                    []*SpecIndex
 	indexMap                   map[string]*SpecIndex
 	indexLock                  sync.Mutex
@@ -364,6 +366,7 @@ func (r *Rolodex) IndexTheRolodex() error {
 // CheckForCircularReferences checks for circular references in the rolodex.
 func (r *Rolodex) CheckForCircularReferences() {
 	if !r.circChecked {
+		started := time.Now()
 		if r.rootIndex != nil && r.rootIndex.resolver != nil {
 			resolvingErrors := r.rootIndex.resolver.CheckForCircularReferences()
 			for e := range resolvingErrors {
@@ -378,6 +381,7 @@ func (r *Rolodex) CheckForCircularReferences() {
 			r.safeCircularReferences = append(r.safeCircularReferences, r.rootIndex.resolver.GetSafeCircularReferences()...)
 			r.infiniteCircularReferences = append(r.infiniteCircularReferences, r.rootIndex.resolver.GetInfiniteCircularReferences()...)
 		}
+		r.circularCheckDuration = time.Since(started)
 		r.circChecked = true
 	}
 }
@@ -471,10 +475,7 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 	var localFile *LocalFile
 	var remoteFile *RemoteFile
 	fileLookup := location
-	isUrl := false
-	if strings.HasPrefix(location, "http") {
-		isUrl = true
-	}
+	isUrl := utils.IsRemoteLocation(location)
 
 	if !isUrl {
 		if len(r.localFS) <= 0 {
@@ -482,12 +483,14 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 			return nil, fmt.Errorf("the rolodex has no local file systems configured, cannot open local file '%s'", location)
 		}
 
-		for k, v := range r.localFS {
+		for _, k := range sortedFileSystemKeys(r.localFS) {
+			v := r.localFS[k]
 
 			// check if this is a URL or an abs/rel reference.
 			if !filepath.IsAbs(location) {
 				fileLookup, _ = filepath.Abs(filepath.Join(k, location))
 			}
+			fileLookup = normalizeLocalPath(fileLookup)
 
 			f, err := v.Open(fileLookup)
 			if err != nil {
@@ -536,7 +539,8 @@ func (r *Rolodex) Open(location string) (RolodexFile, error) {
 				"AllowRemoteLookup to true", fileLookup)
 		}
 
-		for _, v := range r.remoteFS {
+		for _, k := range sortedFileSystemKeys(r.remoteFS) {
+			v := r.remoteFS[k]
 
 			f, err := v.Open(fileLookup)
 			if err != nil {
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/utils"
 	"gopkg.in/yaml.v3"
 	"sync"
 )
@@ -69,6 +70,7 @@ func (l *LocalFS) Open(name string) (fs.File, error) {
 	if !filepath.IsAbs(name) {
 		name, _ = filepath.Abs(filepath.Join(l.baseDirectory, name))
 	}
+	name = normalizeLocalPath(name)
 
 	if f, ok := l.Files.Load(name); ok {
 		return f.(*LocalFile), nil
@@ -158,6 +160,12 @@ func (l *LocalFS) Open(name string) (fs.File, error) {
 	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
 }
 
+// normalizeLocalPath returns the canonical form of a local path, using the separator of the OS. Logically identical
+// paths share the same key, so they are only loaded once.
+func normalizeLocalPath(name string) string {
+	return filepath.FromSlash(utils.NormalizePath(name))
+}
+
 // LocalFile is a file that has been indexed by the LocalFS. It implements the RolodexFile interface.
 type LocalFile struct {
 	filename      string
@@ -307,7 +315,8 @@ type LocalFSConfig struct {
 	// supply your own logger
 	Logger *slog.Logger
 
-	// supply a list of specific files to index only
+	// supply a list of specific files to index only. Entries containing glob metacharacters ('*', '?' or '[') are
+	// treated as filepath.Match patterns, everything else must match a file path exactly. See MatchesFileFilter.
 	FileFilters []string
 
 	// supply a custom fs.FS to use
@@ -363,7 +372,7 @@ func NewLocalFSWithConfig(config *LocalFSConfig) (*LocalFS, error) {
 				return nil
 			}
 			if len(config.FileFilters) > 0 {
-				if !slices.Contains(config.FileFilters, p) {
+				if !MatchesFileFilter(config.FileFilters, p) {
 					return nil
 				}
 			}
@@ -380,6 +389,28 @@ func NewLocalFSWithConfig(config *LocalFSConfig) (*LocalFS, error) {
 	return localFS, nil
 }
 
+// MatchesFileFilter returns true if the path is included by the supplied file filters. An empty filter includes
+// everything. Plain entries are checked first and must match the path exactly. If none match, entries containing
+// glob metacharacters ('*', '?' or '[') are matched as filepath.Match patterns against the whole path, and then
+// against the file name alone, so '*.schema.yaml' includes 'schemas/pet.schema.yaml'. Invalid patterns match nothing.
+func MatchesFileFilter(filters []string, p string) bool {
+	if len(filters) == 0 || slices.Contains(filters, p) {
+		return true
+	}
+	for _, f := range filters {
+		if !strings.ContainsAny(f, "*?[") {
+			continue
+		}
+		if ok, _ := filepath.Match(f, p); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(f, filepath.Base(p)); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (l *LocalFS) extractFile(p string) (*LocalFile, error) {
 	extension := ExtractFileType(p)
 	var readingErrors []error
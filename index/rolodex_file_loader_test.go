@@ -327,3 +327,34 @@ func TestRecursiveLocalFile_MultipleRequests(t *testing.T) {
 		completed++
 	}
 }
+
+func TestRolodexLocalFile_TestGlobFilters(t *testing.T) {
+	testFS := fstest.MapFS{
+		"spec.yaml":                {Data: []byte("hip"), ModTime: time.Now()},
+		"schemas/pet.schema.yaml":  {Data: []byte("pip"), ModTime: time.Now()},
+		"schemas/shop.schema.yaml": {Data: []byte("sip"), ModTime: time.Now()},
+		"schemas/other.yaml":       {Data: []byte("dip"), ModTime: time.Now()},
+	}
+
+	fileFS, _ := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: ".",
+		FileFilters:   []string{"spec.yaml", "*.schema.yaml"},
+		DirFS:         testFS,
+	})
+	files := fileFS.GetFiles()
+	assert.Len(t, files, 3)
+	for k := range files {
+		assert.NotContains(t, k, "other.yaml")
+	}
+}
+
+func TestMatchesFileFilter(t *testing.T) {
+	assert.True(t, MatchesFileFilter(nil, "anything.yaml"))
+	assert.True(t, MatchesFileFilter([]string{"spec.yaml"}, "spec.yaml"))
+	assert.False(t, MatchesFileFilter([]string{"spec.yaml"}, "spec2.yaml"))
+	assert.True(t, MatchesFileFilter([]string{"schemas/*.yaml"}, "schemas/pet.yaml"))
+	assert.False(t, MatchesFileFilter([]string{"schemas/*.yaml"}, "other/pet.yaml"))
+	assert.True(t, MatchesFileFilter([]string{"pet?.yaml"}, "deep/pet1.yaml"))
+	assert.True(t, MatchesFileFilter([]string{"[ab].json"}, "a.json"))
+	assert.False(t, MatchesFileFilter([]string{"[.json"}, "[.json.yaml"))
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// MemoryFS is a read-only, in-memory file system, designed for tests where multi-file specifications need to be
+// resolved without touching the disk or the network. Files are keyed by a slash separated path relative to the
+// base directory (for example 'schemas/pet.yaml'), or by URL (for example 'https://pb33f.io/pet.yaml').
+//
+// Files keyed by path are available through the fs.FS interface, so a MemoryFS can be used as the DirFS of a
+// LocalFS. Files keyed by URL are available through RemoteHandler, which can be used as the RemoteHandlerFunc of a
+// RemoteFS.
+type MemoryFS struct {
+	files   map[string][]byte
+	urls    map[string][]byte
+	modTime time.Time
+}
+
+// NewMemoryFS creates a new MemoryFS containing the supplied files, keyed by path or URL.
+func NewMemoryFS(files map[string][]byte) *MemoryFS {
+	m := &MemoryFS{files: make(map[string][]byte), urls: make(map[string][]byte), modTime: time.Now()}
+	for k, v := range files {
+		if utils.IsRemoteLocation(k) {
+			m.urls[k] = v
+			continue
+		}
+		m.files[path.Clean(strings.TrimPrefix(k, "/"))] = v
+	}
+	return m
+}
+
+// Open returns the file or directory with the supplied path. If there is no such file, an *fs.PathError wrapping
+// fs.ErrNotExist is returned, the same as a missing file on disk.
+func (m *MemoryFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := m.files[name]; ok {
+		return &memoryFile{name: name, data: data, modTime: m.modTime, reader: bytes.NewReader(data)}, nil
+	}
+	if m.isDir(name) {
+		return &memoryFile{name: name, dir: true, modTime: m.modTime, fs: m}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat returns the fs.FileInfo for the file or directory with the supplied path.
+func (m *MemoryFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := m.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f.Stat()
+}
+
+// ReadDir returns the files and directories directly inside the named directory, sorted by name.
+func (m *MemoryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !m.isDir(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	for k := range m.files {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		child, _, isDir := strings.Cut(strings.TrimPrefix(k, prefix), "/")
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		f := &memoryFile{name: prefix + child, dir: isDir, modTime: m.modTime, data: m.files[prefix+child]}
+		entries = append(entries, fs.FileInfoToDirEntry(f))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// RemoteHandler returns the file with the supplied URL as an HTTP response, files that don't exist return a
+// 404 Not Found response. It can be used as the RemoteHandlerFunc of a RemoteFS.
+func (m *MemoryFS) RemoteHandler(url string) (*http.Response, error) {
+	data, ok := m.urls[url]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+	}, nil
+}
+
+func (m *MemoryFS) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	for k := range m.files {
+		if strings.HasPrefix(k, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryFile is a file or directory opened from a MemoryFS.
+type memoryFile struct {
+	name    string
+	data    []byte
+	dir     bool
+	modTime time.Time
+	reader  *bytes.Reader
+	fs      *MemoryFS
+	entries []fs.DirEntry
+	listed  bool
+}
+
+func (f *memoryFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *memoryFile) Close() error               { return nil }
+func (f *memoryFile) Name() string               { return path.Base(f.name) }
+func (f *memoryFile) Size() int64                { return int64(len(f.data)) }
+func (f *memoryFile) ModTime() time.Time         { return f.modTime }
+func (f *memoryFile) IsDir() bool                { return f.dir }
+func (f *memoryFile) Sys() any                   { return nil }
+
+func (f *memoryFile) Mode() fs.FileMode {
+	if f.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (f *memoryFile) Read(b []byte) (int, error) {
+	if f.dir {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrInvalid}
+	}
+	return f.reader.Read(b)
+}
+
+// ReadDir reads the contents of a directory, following the fs.ReadDirFile contract.
+func (f *memoryFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.dir || f.fs == nil {
+		return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if !f.listed {
+		f.entries, _ = f.fs.ReadDir(f.name)
+		f.listed = true
+	}
+	if n <= 0 {
+		entries := f.entries
+		f.entries = nil
+		return entries, nil
+	}
+	if len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(f.entries))
+	entries := f.entries[:n]
+	f.entries = f.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryFS(t *testing.T) {
+	memFS := NewMemoryFS(map[string][]byte{
+		"./spec.yaml":               []byte("hip"),
+		"schemas/pet.yaml":          []byte("hop"),
+		"schemas/nested/owner.yaml": []byte("chop"),
+		"https://pb33f.io/tag.yaml": []byte("shop"),
+	})
+	assert.NoError(t, fstest.TestFS(memFS, "spec.yaml", "schemas/pet.yaml", "schemas/nested/owner.yaml"))
+
+	b, err := fs.ReadFile(memFS, "schemas/pet.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "hop", string(b))
+
+	_, err = memFS.Open("schemas/missing.yaml")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	resp, err := memFS.RemoteHandler("https://pb33f.io/tag.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "shop", string(b))
+
+	resp, _ = memFS.RemoteHandler("https://pb33f.io/missing.yaml")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestMemoryFS_Rolodex(t *testing.T) {
+	memFS := NewMemoryFS(map[string][]byte{
+		"spec.yaml": []byte("hip"),
+	})
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{BaseDirectory: "/tmp", DirFS: memFS})
+	require.NoError(t, err)
+
+	rolo := NewRolodex(CreateOpenAPIIndexConfig())
+	rolo.AddLocalFS("/tmp", fileFS)
+
+	f, err := rolo.Open("spec.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "hip", f.GetContent())
+
+	_, err = rolo.Open("missing.yaml")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
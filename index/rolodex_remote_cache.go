@@ -0,0 +1,161 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// remoteCacheEntry is the metadata stored alongside a cached remote document, used to revalidate it.
+type remoteCacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// remoteCache stores fetched remote documents in a local directory, keyed by URL.
+type remoteCache struct {
+	dir    string
+	client *http.Client
+	logger *slog.Logger
+	ctx    func() context.Context
+}
+
+// newCachingRemoteHandler creates a RemoteURLHandler that reads and writes remote documents to a cache directory.
+//
+// If a cached document has an ETag or Last-Modified value, a conditional request is made, and the cached copy is
+// used if the server responds with 304 Not Modified. Cached documents without either value are fetched again, and
+// the cache is updated. A cache miss fetches the document exactly like the default handler does. If the server
+// cannot be reached (a network error), a cached copy is used when there is one.
+func newCachingRemoteHandler(dir string, client *http.Client, logger *slog.Logger,
+	ctx func() context.Context,
+) utils.RemoteURLHandler {
+	if ctx == nil {
+		ctx = context.Background
+	}
+	c := &remoteCache{dir: dir, client: client, logger: logger, ctx: ctx}
+	return c.fetch
+}
+
+func (c *remoteCache) paths(remoteURL string) (string, string) {
+	sum := sha256.Sum256([]byte(remoteURL))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key+".cache"), filepath.Join(c.dir, key+".json")
+}
+
+func (c *remoteCache) load(remoteURL string) (*remoteCacheEntry, []byte) {
+	bodyPath, metaPath := c.paths(remoteURL)
+	metaBytes, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil
+	}
+	var entry remoteCacheEntry
+	if json.Unmarshal(metaBytes, &entry) != nil || entry.URL != remoteURL {
+		return nil, nil
+	}
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil, nil
+	}
+	return &entry, body
+}
+
+func (c *remoteCache) store(remoteURL string, header http.Header, body []byte) {
+	entry := remoteCacheEntry{
+		URL:          remoteURL,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+	bodyPath, metaPath := c.paths(remoteURL)
+	metaBytes, _ := json.Marshal(entry)
+	err := os.MkdirAll(c.dir, 0o755)
+	if err == nil {
+		err = os.WriteFile(bodyPath, body, 0o644)
+	}
+	if err == nil {
+		err = os.WriteFile(metaPath, metaBytes, 0o644)
+	}
+	if err != nil && c.logger != nil {
+		c.logger.Warn("[rolodex remote cache] unable to cache remote document", "url", remoteURL, "error", err.Error())
+	}
+}
+
+func (c *remoteCache) fetch(remoteURL string) (*http.Response, error) {
+	entry, cached := c.load(remoteURL)
+
+	req, err := http.NewRequestWithContext(c.ctx(), http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// a stale copy is better than nothing when the server cannot be reached, unless fetching was cancelled.
+		if entry != nil && req.Context().Err() == nil {
+			if c.logger != nil {
+				c.logger.Warn("[rolodex remote cache] unable to fetch remote document, using cache", "url", remoteURL,
+					"error", err.Error())
+			}
+			return cachedResponse(entry, cached), nil
+		}
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = resp.Body.Close()
+		if c.logger != nil {
+			c.logger.Debug("[rolodex remote cache] remote document not modified, using cache", "url", remoteURL)
+		}
+		return cachedResponse(entry, cached), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	c.store(remoteURL, resp.Header, body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cachedResponse creates a response for a document served from the cache.
+func cachedResponse(entry *remoteCacheEntry, body []byte) *http.Response {
+	header := make(http.Header)
+	if entry.LastModified != "" {
+		header.Set("Last-Modified", entry.LastModified)
+	}
+	if entry.ETag != "" {
+		header.Set("ETag", entry.ETag)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
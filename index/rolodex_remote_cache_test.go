@@ -0,0 +1,177 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cacheTestSpec = `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object`
+
+func TestRemoteFS_CacheDir_ETag(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetches.Add(1)
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	open := func() string {
+		cfg := CreateOpenAPIIndexConfig()
+		cfg.RemoteCacheDir = cacheDir
+		remoteFS, err := NewRemoteFSWithConfig(cfg)
+		require.NoError(t, err)
+		file, err := remoteFS.Open(server.URL + "/pets.yaml")
+		require.NoError(t, err)
+		b, _ := io.ReadAll(file)
+		return string(b)
+	}
+
+	// first run is a cache miss.
+	assert.Equal(t, cacheTestSpec, open())
+	assert.Equal(t, int32(1), fetches.Load())
+	entries, _ := os.ReadDir(cacheDir)
+	assert.Len(t, entries, 2)
+
+	// second run revalidates and uses the cached copy.
+	assert.Equal(t, cacheTestSpec, open())
+	assert.Equal(t, int32(1), fetches.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestRemoteFS_CacheDir_LastModified_Changed(t *testing.T) {
+	var fetches atomic.Int32
+	body := cacheTestSpec
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "", req.Header.Get("If-None-Match"))
+		fetches.Add(1)
+		rw.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		_, _ = rw.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.RemoteCacheDir = t.TempDir()
+	handler := newCachingRemoteHandler(cfg.RemoteCacheDir, http.DefaultClient, nil, nil)
+
+	resp, err := handler(server.URL + "/pets.yaml")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, cacheTestSpec, string(b))
+
+	// the server ignores the condition and sends new content, which replaces the cached copy.
+	body = "openapi: 3.1.1"
+	resp, err = handler(server.URL + "/pets.yaml")
+	require.NoError(t, err)
+	b, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "openapi: 3.1.1", string(b))
+	assert.Equal(t, int32(2), fetches.Load())
+
+	c := &remoteCache{dir: cfg.RemoteCacheDir}
+	_, cached := c.load(server.URL + "/pets.yaml")
+	assert.Equal(t, "openapi: 3.1.1", string(cached))
+}
+
+func TestRemoteFS_CacheDir_NoValidators(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		n := fetches.Add(1)
+		assert.Empty(t, req.Header.Get("If-None-Match"))
+		assert.Empty(t, req.Header.Get("If-Modified-Since"))
+		if n == 3 {
+			_, _ = rw.Write([]byte("openapi: 3.1.1"))
+			return
+		}
+		_, _ = rw.Write([]byte(cacheTestSpec))
+	}))
+
+	dir := t.TempDir()
+	handler := newCachingRemoteHandler(dir, http.DefaultClient, nil, nil)
+	for i := 0; i < 2; i++ {
+		resp, err := handler(server.URL + "/pets.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		b, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, cacheTestSpec, string(b))
+	}
+
+	// documents without an ETag or Last-Modified value are fetched every time, so changes are picked up.
+	resp, err := handler(server.URL + "/pets.yaml")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "openapi: 3.1.1", string(b))
+	assert.Equal(t, int32(3), fetches.Load())
+
+	c := &remoteCache{dir: dir}
+	_, cached := c.load(server.URL + "/pets.yaml")
+	assert.Equal(t, "openapi: 3.1.1", string(cached))
+
+	// the cached copy is only used when the server cannot be reached.
+	remoteURL := server.URL + "/pets.yaml"
+	server.Close()
+	resp, err = handler(remoteURL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "openapi: 3.1.1", string(b))
+
+	// nothing cached means the network error is returned.
+	_, err = handler(remoteURL + "?missing")
+	assert.Error(t, err)
+}
+
+func TestRemoteFS_CacheDir_ErrorsNotCached(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	handler := newCachingRemoteHandler(dir, http.DefaultClient, nil, nil)
+	for i := 0; i < 2; i++ {
+		resp, err := handler(server.URL + "/pets.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
+
+func TestRemoteFS_CacheDir_IgnoredWithHandler(t *testing.T) {
+	u, _ := url.Parse("https://pb33f.io")
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.BaseURL = u
+	cfg.RemoteCacheDir = t.TempDir()
+	var called bool
+	cfg.RemoteURLHandler = func(url string) (*http.Response, error) {
+		called = true
+		return nil, nil
+	}
+	remoteFS, _ := NewRemoteFSWithConfig(cfg)
+	_, _ = remoteFS.RemoteHandlerFunc("https://pb33f.io/pets.yaml")
+	assert.True(t, called)
+}
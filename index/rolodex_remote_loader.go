@@ -4,6 +4,7 @@
 package index
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,7 +14,6 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/pb33f/libopenapi/datamodel"
@@ -222,12 +222,24 @@ func NewRemoteFSWithConfig(specIndexConfig *SpecIndexConfig) (*RemoteFS, error)
 	if specIndexConfig.RemoteURLHandler != nil {
 		rfs.RemoteHandlerFunc = specIndexConfig.RemoteURLHandler
 	} else {
-		// default http client
-		client := &http.Client{
-			Timeout: time.Second * 120,
+		client := specIndexConfig.HTTPClient
+		if client == nil {
+			// default http client
+			client = &http.Client{
+				Timeout: time.Second * 120,
+			}
 		}
-		rfs.RemoteHandlerFunc = func(url string) (*http.Response, error) {
-			return client.Get(url)
+		if specIndexConfig.RemoteCacheDir != "" {
+			rfs.RemoteHandlerFunc = newCachingRemoteHandler(specIndexConfig.RemoteCacheDir, client, log,
+				rfs.fetchContext)
+		} else {
+			rfs.RemoteHandlerFunc = func(url string) (*http.Response, error) {
+				req, err := http.NewRequestWithContext(rfs.fetchContext(), http.MethodGet, url, nil)
+				if err != nil {
+					return nil, err
+				}
+				return client.Do(req)
+			}
 		}
 	}
 	return rfs, nil
@@ -249,6 +261,14 @@ func (i *RemoteFS) SetRemoteHandlerFunc(handlerFunc utils.RemoteURLHandler) {
 	i.RemoteHandlerFunc = handlerFunc
 }
 
+// fetchContext returns the context used for remote fetches, set by the Context of the index configuration.
+func (i *RemoteFS) fetchContext() context.Context {
+	if i.indexConfig != nil && i.indexConfig.Context != nil {
+		return i.indexConfig.Context
+	}
+	return context.Background()
+}
+
 // SetIndexConfig sets the index configuration.
 func (i *RemoteFS) SetIndexConfig(config *SpecIndexConfig) {
 	i.indexConfig = config
@@ -284,13 +304,19 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 			"AllowRemoteLookup to true as part of the index configuration", remoteURL)
 	}
 
-	if !strings.HasPrefix(remoteURL, "http") {
+	if !utils.IsRemoteLocation(remoteURL) {
 		if i.logger != nil {
 			i.logger.Debug("[rolodex remote loader] not a remote file, ignoring", "file", remoteURL)
 		}
 		return nil, fmt.Errorf("not a remote file: %s", remoteURL)
 	}
 
+	if ctxErr := i.fetchContext().Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	// logically identical URLs share the same key, so they are only fetched once.
+	remoteURL = utils.NormalizePath(remoteURL)
 	remoteParsedURL, err := url.Parse(remoteURL)
 	if err != nil {
 		return nil, err
@@ -313,6 +339,10 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 			"remoteURL", remoteParsedURL.String())
 
 		for !wait.done {
+			if ctxErr := i.fetchContext().Err(); ctxErr != nil {
+				wait.listeners--
+				return nil, ctxErr
+			}
 			i.logger.Debug("[rolodex remote loader] sleeping, waiting for file to return", "file", remoteURL)
 			time.Sleep(500 * time.Nanosecond) // breathe for a few nanoseconds.
 		}
@@ -358,7 +388,7 @@ func (i *RemoteFS) Open(remoteURL string) (fs.File, error) {
 
 	i.logger.Debug("[rolodex remote loader] loading remote file", "file", remoteURL, "remoteURL", remoteParsedURL.String())
 
-	response, clientErr := i.RemoteHandlerFunc(remoteParsedURL.String())
+	response, clientErr := i.fetch(remoteParsedURL.String())
 	if clientErr != nil {
 
 		i.remoteErrors = append(i.remoteErrors, clientErr)
@@ -12,10 +12,12 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var test_httpClient = &http.Client{Timeout: time.Duration(60) * time.Second}
@@ -442,3 +444,23 @@ func TestNewRemoteFS_BadURL(t *testing.T) {
 	assert.Nil(t, x)
 	assert.Error(t, y)
 }
+
+func TestRemoteFS_OpenNormalizedURL(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		assert.Equal(t, "/common/pet.yaml", req.URL.Path)
+		_, _ = rw.Write([]byte("type: object"))
+	}))
+	defer server.Close()
+
+	remoteFS, err := NewRemoteFSWithConfig(CreateOpenAPIIndexConfig())
+	require.NoError(t, err)
+
+	a, err := remoteFS.Open(server.URL + "/common/pet.yaml")
+	require.NoError(t, err)
+	b, err := remoteFS.Open(server.URL + "/schemas/.././common//pet.yaml")
+	require.NoError(t, err)
+	assert.Same(t, a, b)
+	assert.Equal(t, int32(1), fetches.Load())
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultRemoteRetryBackoff is the delay before the first retry, when no RemoteRetryBackoff is configured.
+const defaultRemoteRetryBackoff = time.Second
+
+// defaultRemoteRetryMaxWait is the longest delay before a retry, when no RemoteRetryMaxWait is configured.
+const defaultRemoteRetryMaxWait = 30 * time.Second
+
+// fetch requests a remote document using the RemoteHandlerFunc. If RemoteRetries is configured, requests that fail
+// with an error or a 5xx status are retried, waiting RemoteRetryBackoff before the first retry and doubling the wait
+// for each retry after that. A Retry-After header on a failed response replaces the wait. No wait is longer than
+// RemoteRetryMaxWait. 4xx responses are never retried.
+func (i *RemoteFS) fetch(remoteURL string) (*http.Response, error) {
+	var retries int
+	var backoff, maxWait time.Duration
+	if i.indexConfig != nil {
+		retries = i.indexConfig.RemoteRetries
+		backoff = i.indexConfig.RemoteRetryBackoff
+		maxWait = i.indexConfig.RemoteRetryMaxWait
+	}
+	if backoff <= 0 {
+		backoff = defaultRemoteRetryBackoff
+	}
+	if maxWait <= 0 {
+		maxWait = defaultRemoteRetryMaxWait
+	}
+	for attempt := 0; ; attempt++ {
+		response, err := i.RemoteHandlerFunc(remoteURL)
+		if attempt >= retries || i.fetchContext().Err() != nil || !retryableFetch(response, err) {
+			return response, err
+		}
+		wait := retryWait(backoff, attempt, maxWait)
+		if response != nil {
+			if after, ok := retryAfter(response.Header.Get("Retry-After")); ok {
+				wait = min(after, maxWait)
+			}
+			if response.Body != nil {
+				_, _ = io.Copy(io.Discard, response.Body)
+				_ = response.Body.Close()
+			}
+		}
+		i.logger.Warn("[rolodex remote loader] retrying remote fetch", "remoteURL", remoteURL,
+			"attempt", attempt+1, "wait", wait.String())
+		ctx := i.fetchContext()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryWait returns the wait before retrying a failed attempt, doubling the backoff for every attempt before it, up
+// to maxWait. Doubling stops once maxWait is reached, so large attempt counts cannot overflow.
+func retryWait(backoff time.Duration, attempt int, maxWait time.Duration) time.Duration {
+	wait := backoff
+	for n := 0; n < attempt && wait < maxWait; n++ {
+		wait *= 2
+	}
+	return min(wait, maxWait)
+}
+
+// retryableFetch returns true if a fetch failed in a way that could succeed if tried again.
+func retryableFetch(response *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return response != nil && response.StatusCode >= 500
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
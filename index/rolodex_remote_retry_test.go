@@ -0,0 +1,166 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestFS(t *testing.T, retries int) *RemoteFS {
+	cfg := CreateOpenAPIIndexConfig()
+	cfg.RemoteRetries = retries
+	cfg.RemoteRetryBackoff = time.Millisecond
+	remoteFS, err := NewRemoteFSWithConfig(cfg)
+	require.NoError(t, err)
+	return remoteFS
+}
+
+func TestRemoteFS_Retries_ServerError(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if fetches.Add(1) < 3 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = rw.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	file, err := newRetryTestFS(t, 3).Open(server.URL + "/pets.yaml")
+	require.NoError(t, err)
+	b, _ := io.ReadAll(file)
+	assert.Equal(t, cacheTestSpec, string(b))
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestRemoteFS_Retries_Exhausted(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := newRetryTestFS(t, 2).Open(server.URL + "/pets.yaml")
+	assert.ErrorContains(t, err, "(error 503)")
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestRemoteFS_Retries_NotOnClientError(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newRetryTestFS(t, 3).Open(server.URL + "/pets.yaml")
+	assert.ErrorContains(t, err, "(error 404)")
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestRemoteFS_Retries_Disabled(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := newRetryTestFS(t, 0).Open(server.URL + "/pets.yaml")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestRemoteFS_Retries_HandlerError(t *testing.T) {
+	remoteFS := newRetryTestFS(t, 2)
+	var calls int
+	remoteFS.SetRemoteHandlerFunc(func(url string) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return (&MemoryFS{urls: map[string][]byte{url: []byte(cacheTestSpec)}}).RemoteHandler(url)
+	})
+	_, err := remoteFS.Open("https://pb33f.io/pets.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRemoteFS_Retries_RetryAfter(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if fetches.Add(1) == 1 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	remoteFS := newRetryTestFS(t, 1)
+	remoteFS.indexConfig.RemoteRetryBackoff = time.Hour
+
+	start := time.Now()
+	_, err := remoteFS.Open(server.URL + "/pets.yaml")
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestRemoteFS_Retries_RetryAfterClamped(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if fetches.Add(1) == 1 {
+			rw.Header().Set("Retry-After", "86400")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	remoteFS := newRetryTestFS(t, 1)
+	remoteFS.indexConfig.RemoteRetryMaxWait = 10 * time.Millisecond
+
+	start := time.Now()
+	_, err := remoteFS.Open(server.URL + "/pets.yaml")
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestRetryWait(t *testing.T) {
+	assert.Equal(t, time.Second, retryWait(time.Second, 0, time.Minute))
+	assert.Equal(t, 8*time.Second, retryWait(time.Second, 3, time.Minute))
+	assert.Equal(t, time.Minute, retryWait(time.Second, 10, time.Minute))
+	// shifting by this many attempts would overflow.
+	assert.Equal(t, time.Minute, retryWait(time.Second, 100, time.Minute))
+	assert.Equal(t, time.Minute, retryWait(time.Hour, 0, time.Minute))
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+
+	d, ok = retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	_, ok = retryAfter("")
+	assert.False(t, ok)
+	_, ok = retryAfter("soon")
+	assert.False(t, ok)
+}
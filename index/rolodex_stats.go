@@ -0,0 +1,69 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import "time"
+
+// RolodexStats is a summary of the work done by a Rolodex, reported by GetStats once it has been indexed.
+type RolodexStats struct {
+	// LocalFiles is the number of files loaded from local file systems.
+	LocalFiles int `json:"localFiles"`
+
+	// RemoteFiles is the number of files fetched from remote file systems.
+	RemoteFiles int `json:"remoteFiles"`
+
+	// TotalFileSize is the size of every file loaded, in bytes.
+	TotalFileSize int64 `json:"totalFileSize"`
+
+	// References is the number of references found across every index.
+	References int `json:"references"`
+
+	// ResolvedReferences is the number of references that were successfully located.
+	ResolvedReferences int `json:"resolvedReferences"`
+
+	// CircularReferences is the number of circular references found in the root document.
+	CircularReferences int `json:"circularReferences"`
+
+	// IgnoredCircularReferences is the number of circular references ignored because of the configuration.
+	IgnoredCircularReferences int `json:"ignoredCircularReferences"`
+
+	// IndexingDuration is how long it took to index the rolodex.
+	IndexingDuration time.Duration `json:"indexingDuration"`
+
+	// CircularCheckDuration is how long it took to check for circular references, it is zero if the check was
+	// run as part of indexing.
+	CircularCheckDuration time.Duration `json:"circularCheckDuration"`
+}
+
+// TotalFiles returns the number of local and remote files loaded.
+func (s *RolodexStats) TotalFiles() int {
+	return s.LocalFiles + s.RemoteFiles
+}
+
+// GetStats returns statistics about the files, references and timings of the rolodex. The stats are derived from
+// what the rolodex has already tracked, so are only complete once the rolodex has been indexed.
+func (r *Rolodex) GetStats() *RolodexStats {
+	stats := &RolodexStats{
+		TotalFileSize:             r.RolodexFileSize(),
+		References:                len(r.GetAllReferences()),
+		ResolvedReferences:        len(r.GetAllMappedReferences()),
+		IgnoredCircularReferences: len(r.GetIgnoredCircularReferences()),
+		IndexingDuration:          r.indexingDuration,
+		CircularCheckDuration:     r.circularCheckDuration,
+	}
+	for _, v := range r.localFS {
+		if lfs, ok := v.(RolodexFS); ok {
+			stats.LocalFiles += len(lfs.GetFiles())
+		}
+	}
+	for _, v := range r.remoteFS {
+		if rfs, ok := v.(RolodexFS); ok {
+			stats.RemoteFiles += len(rfs.GetFiles())
+		}
+	}
+	if r.rootIndex != nil {
+		stats.CircularReferences = len(r.rootIndex.GetCircularReferences())
+	}
+	return stats
+}
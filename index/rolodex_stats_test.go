@@ -0,0 +1,99 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRolodex_GetStats(t *testing.T) {
+	baseDir := "rolodex_test_data"
+
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: baseDir,
+		DirFS:         os.DirFS(baseDir),
+	})
+	require.NoError(t, err)
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.SpecFilePath = filepath.Join(baseDir, "doc1.yaml")
+	cf.BasePath = baseDir
+	cf.IgnoreArrayCircularReferences = true
+	cf.IgnorePolymorphicCircularReferences = true
+
+	rolo := NewRolodex(cf)
+	rolo.AddLocalFS(baseDir, fileFS)
+
+	rootBytes, err := os.ReadFile(cf.SpecFilePath)
+	require.NoError(t, err)
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal(rootBytes, &rootNode)
+	rolo.SetRootNode(&rootNode)
+
+	require.NoError(t, rolo.IndexTheRolodex())
+	stats := rolo.GetStats()
+	assert.Equal(t, len(rolo.GetIndexes()), stats.LocalFiles)
+	assert.Zero(t, stats.RemoteFiles)
+	assert.Equal(t, stats.LocalFiles, stats.TotalFiles())
+	assert.Equal(t, rolo.RolodexFileSize(), stats.TotalFileSize)
+	assert.Equal(t, 8, stats.References)
+	assert.Equal(t, 8, stats.ResolvedReferences)
+	assert.Equal(t, rolo.GetIndexingDuration(), stats.IndexingDuration)
+}
+
+func TestRolodex_GetStats_RemoteAndCircular(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`components:
+  schemas:
+    Toy:
+      type: string`))
+	}))
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [owner]
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+        toy:
+          $ref: '` + server.URL + `/toys.yaml#/components/schemas/Toy'
+    Owner:
+      type: object
+      required: [pet]
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'`
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &rootNode)
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.AvoidCircularReferenceCheck = true
+	rolo := NewRolodex(cf)
+	remoteFS, err := NewRemoteFSWithConfig(cf)
+	require.NoError(t, err)
+	rolo.AddRemoteFS(server.URL, remoteFS)
+	rolo.SetRootNode(&rootNode)
+
+	_ = rolo.IndexTheRolodex()
+	rolo.CheckForCircularReferences()
+
+	stats := rolo.GetStats()
+	assert.Zero(t, stats.LocalFiles)
+	assert.Equal(t, 1, stats.RemoteFiles)
+	assert.Equal(t, 1, stats.CircularReferences)
+	assert.NotZero(t, stats.CircularCheckDuration)
+}
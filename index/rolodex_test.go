@@ -90,6 +90,40 @@ func TestRolodex_LocalNativeFS(t *testing.T) {
 
 }
 
+func TestRolodex_OpenLocalFSOrder(t *testing.T) {
+	rootFS := fstest.MapFS{
+		"spec.yaml":      {Data: []byte("root"), ModTime: time.Now()},
+		"http-spec.yaml": {Data: []byte("not a url"), ModTime: time.Now()},
+	}
+	specsFS := fstest.MapFS{
+		"spec.yaml": {Data: []byte("specs"), ModTime: time.Now()},
+	}
+
+	// the most specific file system wins, regardless of the order they were added.
+	for i := 0; i < 10; i++ {
+		first := NewRolodex(CreateOpenAPIIndexConfig())
+		first.AddLocalFS("/tmp", rootFS)
+		first.AddLocalFS("/tmp/specs", specsFS)
+
+		second := NewRolodex(CreateOpenAPIIndexConfig())
+		second.AddLocalFS("/tmp/specs", specsFS)
+		second.AddLocalFS("/tmp", rootFS)
+
+		for _, rolo := range []*Rolodex{first, second} {
+			f, err := rolo.Open("spec.yaml")
+			assert.NoError(t, err)
+			assert.Equal(t, "specs", f.GetContent())
+		}
+	}
+
+	// a local file with a name starting with 'http' is not a URL.
+	rolo := NewRolodex(CreateOpenAPIIndexConfig())
+	rolo.AddLocalFS("/tmp", rootFS)
+	f, err := rolo.Open("http-spec.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "not a url", f.GetContent())
+}
+
 func TestRolodex_LocalNonNativeFS(t *testing.T) {
 
 	t.Parallel()
@@ -1844,3 +1878,48 @@ func TestHumanFileSize(t *testing.T) {
 	assert.Equal(t, "1 MB", HumanFileSize(1024*1024))
 
 }
+
+func TestRolodex_LocalFS_NormalizedPathsLoadOnce(t *testing.T) {
+	tmp := t.TempDir()
+	_ = os.Mkdir(filepath.Join(tmp, "schemas"), 0o755)
+	_ = os.Mkdir(filepath.Join(tmp, "common"), 0o755)
+	_ = os.WriteFile(filepath.Join(tmp, "common", "foo.yaml"), []byte(`openapi: 3.1.0
+components:
+  schemas:
+    Foo:
+      type: string`), 0o644)
+
+	root := `openapi: 3.1.0
+components:
+  schemas:
+    First:
+      $ref: "./schemas/../common/foo.yaml#/components/schemas/Foo"
+    Second:
+      $ref: "common/foo.yaml#/components/schemas/Foo"
+    Third:
+      $ref: "$tmp/schemas/../common/foo.yaml#/components/schemas/Foo"
+    Fourth:
+      $ref: "schemas\\..\\common\\foo.yaml#/components/schemas/Foo"`
+	root = strings.ReplaceAll(root, "$tmp", filepath.ToSlash(tmp))
+
+	cf := CreateOpenAPIIndexConfig()
+	cf.BasePath = tmp
+
+	fileFS, err := NewLocalFSWithConfig(&LocalFSConfig{
+		BaseDirectory: tmp,
+		IndexConfig:   cf,
+	})
+	assert.NoError(t, err)
+
+	rolodex := NewRolodex(cf)
+	rolodex.AddLocalFS(tmp, fileFS)
+
+	var rootNode yaml.Node
+	_ = yaml.Unmarshal([]byte(root), &rootNode)
+	rolodex.SetRootNode(&rootNode)
+
+	assert.NoError(t, rolodex.IndexTheRolodex())
+	assert.Len(t, fileFS.GetFiles(), 1)
+	assert.Len(t, rolodex.GetIndexes(), 1)
+	assert.Len(t, rolodex.GetRootIndex().GetAllReferences(), 1)
+}
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"path/filepath"
 	"strings"
+
+	"github.com/pb33f/libopenapi/utils"
 )
 
 type ContextKey string
@@ -55,7 +57,7 @@ func (index *SpecIndex) SearchIndexForReferenceByReferenceWithContext(ctx contex
 	uri := strings.Split(ref, "#/")
 	if len(uri) == 2 {
 		if uri[0] != "" {
-			if strings.HasPrefix(uri[0], "http") {
+			if utils.IsRemoteLocation(uri[0]) {
 				roloLookup = searchRef.FullDefinition
 			} else {
 				if filepath.IsAbs(uri[0]) {
@@ -83,7 +85,7 @@ func (index *SpecIndex) SearchIndexForReferenceByReferenceWithContext(ctx contex
 		if filepath.IsAbs(uri[0]) {
 			roloLookup = uri[0]
 		} else {
-			if strings.HasPrefix(uri[0], "http") {
+			if utils.IsRemoteLocation(uri[0]) {
 				roloLookup = ref
 			} else {
 				if filepath.Ext(absPath) != "" {
@@ -5,9 +5,12 @@ package index
 
 import (
 	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -103,10 +106,21 @@ func extractDefinitionRequiredRefProperties(schemaNode *yaml.Node, reqRefProps m
 	return reqRefProps
 }
 
+// requiresItems returns true if an array schema must contain at least one item.
+func requiresItems(node *yaml.Node) bool {
+	_, minItems := utils.FindKeyNodeTop("minItems", node.Content)
+	if minItems == nil {
+		return false
+	}
+	n, err := strconv.Atoi(minItems.Value)
+	return err == nil && n > 0
+}
+
 // extractRequiredReferenceProperties returns a map of definition names to the property or properties which reference it within a node
 func extractRequiredReferenceProperties(fulldef string, requiredPropDefNode *yaml.Node, propName string, reqRefProps map[string][]string) map[string][]string {
 	isRef, _, refName := utils.IsNodeRefValue(requiredPropDefNode)
-	if !isRef {
+	if !isRef && requiresItems(requiredPropDefNode) {
+		// an array that can be empty ends the recursion, so items are only required if at least one is.
 		_, defItems := utils.FindKeyNodeTop("items", requiredPropDefNode.Content)
 		if defItems != nil {
 			isRef, _, refName = utils.IsNodeRefValue(defItems)
@@ -119,13 +133,13 @@ func extractRequiredReferenceProperties(fulldef string, requiredPropDefNode *yam
 
 	defPath := fulldef
 
-	if strings.HasPrefix(refName, "http") || filepath.IsAbs(refName) {
+	if utils.IsRemoteLocation(refName) || filepath.IsAbs(refName) {
 		defPath = refName
 	} else {
 		exp := strings.Split(fulldef, "#/")
 		if len(exp) == 2 {
 			if exp[0] != "" {
-				if strings.HasPrefix(exp[0], "http") {
+				if utils.IsRemoteLocation(exp[0]) {
 					u, _ := url.Parse(exp[0])
 					r := strings.Split(refName, "#/")
 					if len(r) == 2 {
@@ -170,7 +184,7 @@ func extractRequiredReferenceProperties(fulldef string, requiredPropDefNode *yam
 				defPath = refName
 			}
 		} else {
-			if strings.HasPrefix(exp[0], "http") {
+			if utils.IsRemoteLocation(exp[0]) {
 				u, _ := url.Parse(exp[0])
 				r := strings.Split(refName, "#/")
 				if len(r) == 2 {
@@ -570,7 +584,7 @@ func GenerateCleanSpecConfigBaseURL(baseURL *url.URL, dir string, includeFile bo
 		}
 		cleanedPath = fmt.Sprintf("%s/%s", strings.Join(pathSegs, "/"), strings.Join(cleanedSegs, "/"))
 	} else {
-		if !strings.HasPrefix(dir, "http") {
+		if !utils.IsRemoteLocation(dir) {
 			if len(pathSegs) > 1 || len(dirSegs) > 1 {
 				cleanedPath = fmt.Sprintf("%s/%s", strings.Join(pathSegs, "/"), strings.Join(dirSegs, "/"))
 			}
@@ -579,7 +593,7 @@ func GenerateCleanSpecConfigBaseURL(baseURL *url.URL, dir string, includeFile bo
 		}
 	}
 	var p string
-	if baseURL.Scheme != "" && !strings.HasPrefix(dir, "http") {
+	if baseURL.Scheme != "" && !utils.IsRemoteLocation(dir) {
 		p = fmt.Sprintf("%s://%s%s", baseURL.Scheme, baseURL.Host, cleanedPath)
 	} else {
 		if !strings.Contains(cleanedPath, "/") {
@@ -605,3 +619,19 @@ func syncMapToMap[K comparable, V any](sm *sync.Map) map[K]V {
 
 	return m
 }
+
+// sortedFileSystemKeys returns the keys of a map of file systems, most specific (longest) first, so the
+// rolodex always searches file systems in the same order, regardless of the order they were added.
+func sortedFileSystemKeys(fileSystems map[string]fs.FS) []string {
+	keys := make([]string, 0, len(fileSystems))
+	for k := range fileSystems {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -85,6 +85,7 @@ func (o *Map[K, V]) ToYamlNode(n NodeBuilder, l any) *yaml.Node {
 			Value:    pair.Value(),
 			KeyStyle: keyStyle,
 			LowValue: lv,
+			KeyNode:  keyNode,
 		})
 		i++
 	}
@@ -0,0 +1,73 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DuplicateKey represents a mapping key that is defined more than once in the same YAML (or JSON) object.
+// YAML decoders silently keep the last value, so every value except the last one is lost.
+type DuplicateKey struct {
+	Key     string // the duplicated key.
+	Path    string // a JSON pointer to the duplicated key, for example '/components/schemas/Pet'.
+	Lines   []int  // the line number of every definition of the key, in order.
+	Columns []int  // the column number of every definition of the key, in order.
+}
+
+// Error returns a description of the duplicate key, with the line numbers of every definition.
+func (d *DuplicateKey) Error() string {
+	lines := make([]string, len(d.Lines))
+	for i := range d.Lines {
+		lines[i] = fmt.Sprintf("%d:%d", d.Lines[i], d.Columns[i])
+	}
+	return fmt.Sprintf("duplicate key '%s' found at '%s' [%s]", d.Key, d.Path, strings.Join(lines, ", "))
+}
+
+// FindDuplicateKeys walks a YAML node tree and returns every mapping key that is defined more than once, in the
+// order the duplicates are found. Merge keys ('<<') are ignored, and aliases are not followed.
+func FindDuplicateKeys(node *yaml.Node) []*DuplicateKey {
+	var found []*DuplicateKey
+	findDuplicateKeys(node, "", &found)
+	return found
+}
+
+func findDuplicateKeys(node *yaml.Node, path string, found *[]*DuplicateKey) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			findDuplicateKeys(n, path, found)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			findDuplicateKeys(n, path+"/"+strconv.Itoa(i), found)
+		}
+	case yaml.MappingNode:
+		seen := make(map[string]*DuplicateKey)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k := node.Content[i]
+			if k.Value == "<<" {
+				continue
+			}
+			keyPath := path + "/" + EscapeJSONPointer(k.Value)
+			if d, ok := seen[k.Value]; ok {
+				if len(d.Lines) == 1 {
+					*found = append(*found, d)
+				}
+				d.Lines = append(d.Lines, k.Line)
+				d.Columns = append(d.Columns, k.Column)
+			} else {
+				seen[k.Value] = &DuplicateKey{Key: k.Value, Path: keyPath, Lines: []int{k.Line}, Columns: []int{k.Column}}
+			}
+			findDuplicateKeys(node.Content[i+1], keyPath, found)
+		}
+	}
+}
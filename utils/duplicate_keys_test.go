@@ -0,0 +1,60 @@
+// Copyright 2024 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFindDuplicateKeys(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/{id}:
+    get:
+      operationId: one
+      operationId: two
+components:
+  schemas:
+    Pet:
+      type: object
+    Pet:
+      type: string
+    Pet:
+      type: integer
+tags:
+  - name: a
+    name: b
+paths: {}`
+
+	var root yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(spec), &root))
+
+	dupes := FindDuplicateKeys(&root)
+	assert.Len(t, dupes, 4)
+
+	assert.Equal(t, "/paths/~1a~1{id}/get/operationId", dupes[0].Path)
+	assert.Equal(t, "duplicate key 'operationId' found at '/paths/~1a~1{id}/get/operationId' [5:7, 6:7]",
+		dupes[0].Error())
+
+	assert.Equal(t, "/components/schemas/Pet", dupes[1].Path)
+	assert.Equal(t, []int{9, 11, 13}, dupes[1].Lines)
+
+	assert.Equal(t, "/tags/0/name", dupes[2].Path)
+	assert.Equal(t, []int{16, 17}, dupes[2].Lines)
+
+	// duplicates are reported in the order they are found.
+	assert.Equal(t, "paths", dupes[3].Key)
+	assert.Equal(t, "/paths", dupes[3].Path)
+	assert.Equal(t, []int{2, 18}, dupes[3].Lines)
+}
+
+func TestFindDuplicateKeys_None(t *testing.T) {
+	var root yaml.Node
+	_ = yaml.Unmarshal([]byte("a: &anchor\n  b: 1\nc:\n  <<: *anchor\n  d: 2"), &root)
+	assert.Empty(t, FindDuplicateKeys(&root))
+	assert.Empty(t, FindDuplicateKeys(nil))
+}
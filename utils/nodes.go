@@ -84,3 +84,20 @@ func CreateFloatNode(str string) *yaml.Node {
 	}
 	return n
 }
+
+// CopyComments will copy any head, line and foot comments from one node to another. Comments that already exist
+// on the target node are not overwritten.
+func CopyComments(from, to *yaml.Node) {
+	if from == nil || to == nil {
+		return
+	}
+	if to.HeadComment == "" {
+		to.HeadComment = from.HeadComment
+	}
+	if to.LineComment == "" {
+		to.LineComment = from.LineComment
+	}
+	if to.FootComment == "" {
+		to.FootComment = from.FootComment
+	}
+}
@@ -60,3 +60,20 @@ func TestCreateYamlNode(t *testing.T) {
 	assert.Equal(t, "!!str", y.Tag)
 	assert.Equal(t, "foo", y.Value)
 }
+
+func TestCopyComments(t *testing.T) {
+	from := CreateStringNode("foo")
+	from.HeadComment = "# head"
+	from.LineComment = "# line"
+	from.FootComment = "# foot"
+
+	to := CreateStringNode("bar")
+	to.HeadComment = "# keep me"
+	CopyComments(from, to)
+	assert.Equal(t, "# keep me", to.HeadComment)
+	assert.Equal(t, "# line", to.LineComment)
+	assert.Equal(t, "# foot", to.FootComment)
+
+	CopyComments(nil, to)
+	CopyComments(from, nil)
+}
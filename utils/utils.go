@@ -561,6 +561,17 @@ func IsNodeBoolValue(node *yaml.Node) bool {
 	return n.Tag == "!!bool"
 }
 
+// IsRemoteLocation returns true if a reference or location is an absolute http(s) URL, anything else is
+// treated as a local (relative or absolute) file path.
+func IsRemoteLocation(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// EscapeJSONPointer escapes a single JSON pointer segment (RFC 6901), '~' becomes '~0' and '/' becomes '~1'.
+func EscapeJSONPointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
+
 func IsNodeRefValue(node *yaml.Node) (bool, *yaml.Node, string) {
 	if node == nil {
 		return false, nil, ""
@@ -834,6 +834,21 @@ func TestDetectCase(t *testing.T) {
 	assert.Equal(t, UnknownCase, DetectCase(""))
 }
 
+func TestIsRemoteLocation(t *testing.T) {
+	assert.True(t, IsRemoteLocation("https://pb33f.io/openapi.yaml"))
+	assert.True(t, IsRemoteLocation("http://pb33f.io/openapi.yaml#/components/schemas/Pet"))
+	assert.False(t, IsRemoteLocation("http-schemas.yaml"))
+	assert.False(t, IsRemoteLocation("./https/pet.yaml"))
+	assert.False(t, IsRemoteLocation("/tmp/openapi.yaml"))
+}
+
+func TestEscapeJSONPointer(t *testing.T) {
+	assert.Equal(t, "pets", EscapeJSONPointer("pets"))
+	assert.Equal(t, "~1pets~1{id}", EscapeJSONPointer("/pets/{id}"))
+	assert.Equal(t, "a~0b~1c", EscapeJSONPointer("a~b/c"))
+	assert.Equal(t, "~01", EscapeJSONPointer("~1"))
+}
+
 func TestIsNodeRefValue(t *testing.T) {
 	f := &yaml.Node{
 		Value: "$ref",
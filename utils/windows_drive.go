@@ -1,28 +1,114 @@
 package utils
 
 import (
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+// ReplaceWindowsDriveWithLinuxPath converts a Windows path into a forward-slash path, dropping the drive letter.
+// For example 'C:\schemas\foo.yaml' becomes '/schemas/foo.yaml'.
 func ReplaceWindowsDriveWithLinuxPath(path string) string {
-	if len(path) > 1 && path[1] == ':' {
-		path = strings.ReplaceAll(path, "\\", "/")
+	path = normalizeBackslashes(path)
+	if hasWindowsDrive(path) {
 		return path[2:]
 	}
+	return path
+}
+
+// ReplaceWindowsDriveWithLinuxPathKeepDrive converts a Windows path into a forward-slash path, keeping the drive
+// letter as the first (lowercase) segment, MSYS style. For example 'C:\schemas\foo.yaml' becomes
+// '/c/schemas/foo.yaml'. This keeps paths on different drives from colliding.
+func ReplaceWindowsDriveWithLinuxPathKeepDrive(path string) string {
+	path = normalizeBackslashes(path)
+	if hasWindowsDrive(path) {
+		rest := path[2:]
+		if rest != "" && !strings.HasPrefix(rest, "/") {
+			rest = "/" + rest
+		}
+		return "/" + strings.ToLower(path[:1]) + rest
+	}
+	return path
+}
+
+var schemeAndHost = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/]*`)
+
+// NormalizePath returns a canonical form of a local path or URL, without touching the file system. Backslashes
+// become forward slashes, '.' segments are removed, '..' segments are resolved and repeated slashes are collapsed.
+// A leading slash, a Windows drive letter and a URL scheme and host are kept, as is anything after a '#' (and
+// after a '?' for URLs). Leading '..' segments of a relative path can't be resolved, so they are kept.
+//
+// For example './schemas/../common//foo.yaml' becomes 'common/foo.yaml', and
+// 'https://pb33f.io/a/./b/../c.yaml#/Pet' becomes 'https://pb33f.io/a/c.yaml#/Pet'.
+func NormalizePath(p string) string {
+	if p == "" {
+		return p
+	}
+	var prefix, suffix string
+	if i := strings.Index(p, "#"); i >= 0 {
+		p, suffix = p[:i], p[i:]
+		if p == "" {
+			return suffix
+		}
+	}
+	if loc := schemeAndHost.FindStringIndex(p); loc != nil {
+		prefix, p = p[:loc[1]], p[loc[1]:]
+		if i := strings.Index(p, "?"); i >= 0 {
+			p, suffix = p[:i], p[i:]+suffix
+		}
+		if p == "" {
+			return prefix + suffix
+		}
+	}
+	p = normalizeBackslashes(p)
+	if hasWindowsDrive(p) {
+		prefix, p = p[:2], p[2:]
+		if p == "" {
+			return prefix + suffix
+		}
+	}
+	return prefix + path.Clean(p) + suffix
+}
+
+func normalizeBackslashes(path string) string {
 	return strings.ReplaceAll(path, "\\", "/")
 }
 
+func hasWindowsDrive(path string) bool {
+	return len(path) > 1 && path[1] == ':'
+}
+
+// CheckPathOverlap joins pathA and pathB together. If a run of trailing segments of pathA is the same as a run of
+// leading segments of pathB, the paths are spliced on the longest such run so the shared segments are not repeated.
+// For example 'foo/bar/baz' and 'bar/baz/qux' become 'foo/bar/baz/qux'. If there is no overlap, the paths are
+// simply joined.
 func CheckPathOverlap(pathA, pathB, sep string) string {
 	a := strings.Split(pathA, sep)
 	b := strings.Split(pathB, sep)
-	if strings.HasPrefix(a[len(a)-1], "/") && a[len(a)-1][1:] == b[0] {
-		b = b[1:]
-	}
-	if a[len(a)-1] == b[0] {
-		b = b[1:]
+
+	for k := min(len(a), len(b)); k > 0; k-- {
+		if segmentsOverlap(a[len(a)-k:], b[:k]) {
+			b = b[k:]
+			break
+		}
 	}
 	f := filepath.Join(pathA, strings.Join(b, sep))
 
 	return f
 }
+
+// segmentsOverlap returns true if the suffix segments of one path match the prefix segments of another. The first
+// suffix segment may carry a leading slash (when a path was split on a different separator).
+func segmentsOverlap(suffix, prefix []string) bool {
+	for i := range suffix {
+		s := suffix[i]
+		if i == 0 && s != prefix[i] {
+			s = strings.TrimPrefix(s, "/")
+		}
+		if s != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
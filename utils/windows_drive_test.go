@@ -86,3 +86,83 @@ func TestCheckPathOverlap_VariationB(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, result)
 	}
 }
+
+func TestCheckPathOverlap_Segments(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("paths are joined using the OS separator")
+	}
+	tests := []struct {
+		name     string
+		pathA    string
+		pathB    string
+		expected string
+	}{
+		{"no overlap", "foo/bar", "baz/qux.yaml", "foo/bar/baz/qux.yaml"},
+		{"no overlap, absolute", "/foo/bar", "baz/qux.yaml", "/foo/bar/baz/qux.yaml"},
+		{"single segment", "foo/bar", "bar/qux.yaml", "foo/bar/qux.yaml"},
+		{"multi segment", "foo/bar/baz", "bar/baz/qux", "foo/bar/baz/qux"},
+		{"multi segment, absolute", "/foo/bar/baz", "bar/baz/qux", "/foo/bar/baz/qux"},
+		{"longest overlap wins", "a/b/a/b", "a/b/c", "a/b/a/b/c"},
+		{"repeated segment", "schemas/schemas", "schemas/schemas/pet.yaml", "schemas/schemas/pet.yaml"},
+		{"not a suffix", "foo/bar/baz", "bar/qux", "foo/bar/baz/bar/qux"},
+		{"whole path", "foo/bar", "foo/bar", "foo/bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckPathOverlap(tt.pathA, tt.pathB, "/")
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReplaceWindowsDriveWithLinuxPathKeepDrive(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{`C:\schemas\foo.yaml`, `/c/schemas/foo.yaml`},
+		{`D:\schemas\foo.yaml`, `/d/schemas/foo.yaml`},
+		{`c:/schemas/foo.yaml`, `/c/schemas/foo.yaml`},
+		{`C:schemas\foo.yaml`, `/c/schemas/foo.yaml`},
+		{`C:`, `/c`},
+		{`/do/not/replace/this/path`, `/do/not/replace/this/path`},
+		{`relative\path.yaml`, `relative/path.yaml`},
+	}
+	for _, tt := range tests {
+		result := ReplaceWindowsDriveWithLinuxPathKeepDrive(tt.path)
+		if result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{``, ``},
+		{`./schemas/../common/foo.yaml`, `common/foo.yaml`},
+		{`schemas//common///foo.yaml`, `schemas/common/foo.yaml`},
+		{`.\schemas\..\common/foo.yaml`, `common/foo.yaml`},
+		{`/specs/./schemas/../foo.yaml`, `/specs/foo.yaml`},
+		{`/../foo.yaml`, `/foo.yaml`},
+		{`../../foo.yaml`, `../../foo.yaml`},
+		{`a/../../foo.yaml`, `../foo.yaml`},
+		{`C:\specs\.\schemas\..\foo.yaml`, `C:/specs/foo.yaml`},
+		{`foo.yaml#/components/schemas/../Pet`, `foo.yaml#/components/schemas/../Pet`},
+		{`#/components/schemas/Pet`, `#/components/schemas/Pet`},
+		{`https://pb33f.io/a/./b/../c.yaml#/Pet`, `https://pb33f.io/a/c.yaml#/Pet`},
+		{`https://pb33f.io//a//c.yaml?v=../1`, `https://pb33f.io/a/c.yaml?v=../1`},
+		{`https://pb33f.io`, `https://pb33f.io`},
+		{`https://pb33f.io/`, `https://pb33f.io/`},
+	}
+	for _, tt := range tests {
+		result := NormalizePath(tt.path)
+		if result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+}